@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/repository"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+	"task-management-api/pkg/database"
+)
+
+const usage = `Usage: admin [flags] <command>
+
+Commands:
+  bootstrap <email>    Make the user the first admin, creating the account
+                       if needed. Does nothing once an admin exists
+  list                 List the admins
+
+Flags:
+`
+
+func main() {
+	password := flag.String("password", os.Getenv("BOOTSTRAP_ADMIN_PASSWORD"), "password for a new admin account (default $BOOTSTRAP_ADMIN_PASSWORD)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.NewPostgresDB(cfg.Database.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	adminService := service.NewAdminService(
+		repository.NewUserRepository(db.DB),
+		repository.NewOrgRepository(db.DB),
+		repository.NewInvitationRepository(db.DB),
+		cfg,
+	)
+
+	switch args[0] {
+	case "bootstrap":
+		if len(args) < 2 {
+			log.Fatal("bootstrap requires an email address")
+		}
+		err = bootstrap(adminService, args[1], *password)
+	case "list":
+		err = listAdmins(adminService)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("Failed: %v", err)
+	}
+}
+
+func bootstrap(adminService service.AdminService, email, password string) error {
+	if err := util.ValidateEmail(email); err != nil {
+		return err
+	}
+	if password != "" {
+		if err := util.ValidatePassword(password); err != nil {
+			return err
+		}
+	}
+
+	user, err := adminService.Bootstrap(email, password)
+	if errors.Is(err, service.ErrAdminExists) {
+		fmt.Println("An admin already exists; ask an admin for an invitation instead")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s is now an admin\n", user.Email)
+	return nil
+}
+
+func listAdmins(adminService service.AdminService) error {
+	admins, err := adminService.ListAdmins()
+	if err != nil {
+		return err
+	}
+
+	for _, admin := range admins {
+		fmt.Printf("%s  %s\n", admin.ID, admin.Email)
+	}
+	return nil
+}
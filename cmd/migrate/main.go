@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/pkg/database"
+)
+
+const usage = `Usage: migrate [flags] <command>
+
+Commands:
+  up               Apply all pending migrations
+  down N           Roll back the N most recently applied migrations
+  status           List migrations and whether they are applied
+  create <name>    Create a new empty up/down migration pair
+
+Flags:
+`
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "print the SQL that would be executed without running it")
+	dir := flag.String("dir", "pkg/database/migrations", "migrations directory used by create")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// create only touches the source tree and needs no database
+	if args[0] == "create" {
+		if len(args) < 2 {
+			log.Fatal("create requires a migration name")
+		}
+		paths, err := database.CreateMigration(*dir, strings.Join(args[1:], "_"))
+		if err != nil {
+			log.Fatalf("Failed to create migration: %v", err)
+		}
+		for _, path := range paths {
+			fmt.Println("Created", path)
+		}
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.NewPostgresDB(cfg.Database.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	migrator.DryRun = *dryRun
+	migrator.Out = os.Stdout
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		if len(args) < 2 {
+			log.Fatal("down requires the number of migrations to roll back")
+		}
+		n, convErr := strconv.Atoi(args[1])
+		if convErr != nil || n < 1 {
+			log.Fatalf("Invalid migration count %q", args[1])
+		}
+		err = migrator.Down(n)
+	case "status":
+		err = printStatus(migrator)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+}
+
+func printStatus(migrator *database.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%04d  %-40s %s\n", status.Version, status.Name, applied)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"os/signal"
@@ -10,9 +11,11 @@ import (
 
 	"task-management-api/internal/config"
 	"task-management-api/internal/handler"
+	"task-management-api/internal/policy"
 	"task-management-api/internal/repository"
 	"task-management-api/internal/routes"
 	"task-management-api/internal/service"
+	"task-management-api/internal/util"
 	"task-management-api/pkg/database"
 
 	"github.com/gofiber/fiber/v2"
@@ -43,11 +46,49 @@ func main() {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db.DB)
 	taskRepo := repository.NewTaskRepository(db.DB)
+	tokenRepo := repository.NewTokenRepository(db.DB)
+	jobRepo := repository.NewJobRepository(db.DB)
+	labelRepo := repository.NewLabelRepository(db.DB)
+	dependencyRepo := repository.NewDependencyRepository(db.DB)
+	workflowRepo := repository.NewWorkflowRepository(db.DB)
+	assigneeRepo := repository.NewAssigneeRepository(db.DB)
+	orgRepo := repository.NewOrgRepository(db.DB)
+	invitationRepo := repository.NewInvitationRepository(db.DB)
+	emailChangeRepo := repository.NewEmailChangeRepository(db.DB)
+	resetRepo := repository.NewPasswordResetRepository(db.DB)
+	verifyRepo := repository.NewEmailVerificationRepository(db.DB)
+	mfaRepo := repository.NewMFARepository(db.DB)
+	attemptRepo := repository.NewLoginAttemptRepository(db.DB)
+	eventRepo := repository.NewSecurityEventRepository(db.DB)
+	accessTokenRepo := repository.NewAccessTokenRepository(db.DB)
+
+	// Emails go out through the configured transport
+	mailer := service.NewMailer(cfg.Mail)
+
+	// Authorization rules shared by all services
+	permissions := policy.New()
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg)
-	taskService := service.NewTaskService(taskRepo)
-	workerService := service.NewWorkerService(taskRepo, cfg)
+	verificationService := service.NewEmailVerificationService(userRepo, verifyRepo, mailer, cfg)
+	mfaService := service.NewMFAService(userRepo, mfaRepo, cfg)
+	loginGuard := service.NewLoginGuard(attemptRepo, eventRepo, cfg)
+	accessTokenService := service.NewAccessTokenService(accessTokenRepo, userRepo, orgRepo)
+	authService := service.NewAuthService(userRepo, tokenRepo, orgRepo, verificationService, mfaService, loginGuard, accessTokenService, cfg)
+	taskService := service.NewTaskService(taskRepo, labelRepo, workflowRepo, assigneeRepo, orgRepo, permissions, cfg)
+	labelService := service.NewLabelService(labelRepo, taskRepo, assigneeRepo, permissions)
+	dependencyService := service.NewDependencyService(dependencyRepo, taskRepo, assigneeRepo, permissions)
+	workflowService := service.NewWorkflowService(workflowRepo, orgRepo, permissions)
+	orgService := service.NewOrgService(orgRepo, userRepo, permissions)
+	adminService := service.NewAdminService(userRepo, orgRepo, invitationRepo, cfg)
+	userService := service.NewUserService(userRepo, tokenRepo, adminService, mfaService, accessTokenService)
+	profileService := service.NewProfileService(userRepo, tokenRepo, emailChangeRepo, accessTokenService, mailer, cfg)
+	passwordResetService := service.NewPasswordResetService(userRepo, tokenRepo, resetRepo, accessTokenService, mailer, cfg)
+	workerService := service.NewWorkerService(taskRepo, jobRepo, tokenRepo, mfaRepo, workflowRepo, service.NewLogEventPublisher(), permissions, cfg)
+
+	// Create the first admin from the configuration
+	if cfg.Admin.Email != "" {
+		bootstrapAdmin(adminService, cfg.Admin.Email, cfg.Admin.Password)
+	}
 
 	// Start worker service with context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -57,6 +98,19 @@ func main() {
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
 	taskHandler := handler.NewTaskHandler(taskService, workerService)
+	labelHandler := handler.NewLabelHandler(labelService)
+	dependencyHandler := handler.NewDependencyHandler(dependencyService)
+	workflowHandler := handler.NewWorkflowHandler(workflowService)
+	orgHandler := handler.NewOrgHandler(orgService)
+	permissionHandler := handler.NewPermissionHandler(permissions, taskService)
+	adminHandler := handler.NewAdminHandler(adminService)
+	userHandler := handler.NewUserHandler(userService)
+	profileHandler := handler.NewProfileHandler(profileService)
+	passwordResetHandler := handler.NewPasswordResetHandler(passwordResetService)
+	verificationHandler := handler.NewEmailVerificationHandler(verificationService)
+	mfaHandler := handler.NewMFAHandler(mfaService)
+	securityHandler := handler.NewSecurityHandler(loginGuard)
+	accessTokenHandler := handler.NewAccessTokenHandler(accessTokenService)
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
@@ -69,11 +123,11 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
+		AllowMethods: "GET, POST, PUT, PATCH, DELETE, OPTIONS",
 	}))
 
 	// Setup Routes
-	routes.SetupRoutes(app, authHandler, taskHandler, authService)
+	routes.SetupRoutes(app, authHandler, taskHandler, labelHandler, dependencyHandler, workflowHandler, orgHandler, permissionHandler, adminHandler, userHandler, profileHandler, passwordResetHandler, verificationHandler, mfaHandler, securityHandler, accessTokenHandler, authService, workerService, cfg)
 
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
@@ -97,16 +151,27 @@ func main() {
 }
 
 func customErrorHandler(c *fiber.Ctx, err error) error {
-	code := fiber.StatusInternalServerError
-	message := "Internal Server Error"
+	return util.SendError(c, err)
+}
 
-	if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
-		message = e.Message
+// bootstrapAdmin makes the configured user the first admin. Once an admin
+// exists it does nothing, so the settings can stay in place.
+func bootstrapAdmin(adminService service.AdminService, email, password string) {
+	if err := util.ValidateEmail(email); err != nil {
+		log.Fatalf("Invalid BOOTSTRAP_ADMIN_EMAIL: %v", err)
+	}
+	if password != "" {
+		if err := util.ValidatePassword(password); err != nil {
+			log.Fatalf("Invalid BOOTSTRAP_ADMIN_PASSWORD: %v", err)
+		}
 	}
 
-	return c.Status(code).JSON(fiber.Map{
-		"error":   message,
-		"message": err.Error(),
-	})
+	user, err := adminService.Bootstrap(email, password)
+	if errors.Is(err, service.ErrAdminExists) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to bootstrap admin: %v", err)
+	}
+	log.Printf("Bootstrapped admin %s", user.Email)
 }
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"time"
 
+	"task-management-api/internal/domain"
+
 	"github.com/joho/godotenv"
 )
 
@@ -14,6 +16,14 @@ type Config struct {
 	JWT      JWTConfig
 	Server   ServerConfig
 	Worker   WorkerConfig
+	Subtasks SubtaskConfig
+	Admin    AdminConfig
+	Account  AccountConfig
+	Mail     MailConfig
+	Reset    PasswordResetConfig
+	Verify   VerificationConfig
+	MFA      MFAConfig
+	Login    LoginProtectionConfig
 }
 
 type DatabaseConfig struct {
@@ -26,8 +36,9 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret string
-	Expiry time.Duration
+	Secret        string
+	Expiry        time.Duration
+	RefreshExpiry time.Duration
 }
 
 type ServerConfig struct {
@@ -36,15 +47,111 @@ type ServerConfig struct {
 
 type WorkerConfig struct {
 	AutoCompleteMinutes int
+	PollInterval        time.Duration
+	LockTimeout         time.Duration
+	MaxAttempts         int
+	RetryBaseDelay      time.Duration
+	JobRetention        time.Duration
+}
+
+// SubtaskConfig limits task hierarchies and controls what happens to the
+// subtasks of a task that is deleted or completed.
+type SubtaskConfig struct {
+	MaxDepth   int
+	OnDelete   domain.SubtaskAction
+	OnComplete domain.SubtaskAction
+}
+
+// AdminConfig controls how platform admins come into existence. When Email is
+// set and there is no admin yet, the server makes that user the first admin
+// on startup, creating the account with Password if it does not exist.
+type AdminConfig struct {
+	Email            string
+	Password         string
+	InvitationExpiry time.Duration
+}
+
+// AccountConfig covers self-service account changes. Retention decides
+// whether deleted accounts are anonymized or removed with their tasks.
+type AccountConfig struct {
+	Retention         domain.AccountRetention
+	EmailChangeExpiry time.Duration
+}
+
+// MailConfig selects how emails are sent: written to the log, appended to
+// File, or delivered through the SMTP server.
+type MailConfig struct {
+	Driver       string
+	From         string
+	File         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// PasswordResetConfig limits the forgot-password requests per account and per
+// client IP within Window.
+type PasswordResetConfig struct {
+	Expiry        time.Duration
+	Window        time.Duration
+	MaxPerAccount int
+	MaxPerIP      int
+}
+
+// VerificationConfig covers email verification. Policy decides what
+// unverified users cannot do; a new token is mailed at most once per
+// ResendInterval, and each client IP may ask for MaxPerIP tokens within
+// Window.
+type VerificationConfig struct {
+	Policy         domain.VerificationPolicy
+	Expiry         time.Duration
+	ResendInterval time.Duration
+	Window         time.Duration
+	MaxPerIP       int
+}
+
+// MFAConfig covers two-factor authentication. Issuer names the service in
+// authenticator apps and EncryptionKey protects the stored TOTP secrets; a
+// login challenge allows MaxAttempts codes within ChallengeExpiry. After
+// MaxFailures wrong codes across challenges, a user gets no challenge until
+// LockDuration has passed since the last one.
+type MFAConfig struct {
+	Issuer          string
+	EncryptionKey   string
+	ChallengeExpiry time.Duration
+	MaxAttempts     int
+	MaxFailures     int
+	LockDuration    time.Duration
+}
+
+// LoginProtectionConfig limits failed logins. Failures are counted per
+// account email and per client IP within Window. From the DelayAfter-th
+// failure on, an account must wait BaseDelay, doubling with every further
+// failure up to MaxDelay, before the next attempt; at AccountLockThreshold
+// or IPLockThreshold failures the account or IP is locked for LockDuration.
+type LoginProtectionConfig struct {
+	Window               time.Duration
+	DelayAfter           int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	AccountLockThreshold int
+	IPLockThreshold      int
+	LockDuration         time.Duration
 }
 
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
-	jwtExpiryHours, err := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "24"))
+	jwtExpiryMinutes, err := strconv.Atoi(getEnv("JWT_EXPIRY_MINUTES", "15"))
 	if err != nil {
-		jwtExpiryHours = 24
+		jwtExpiryMinutes = 15
+	}
+
+	refreshExpiryHours, err := strconv.Atoi(getEnv("JWT_REFRESH_EXPIRY_HOURS", "720"))
+	if err != nil {
+		refreshExpiryHours = 720
 	}
 
 	autoCompleteMinutes, err := strconv.Atoi(getEnv("AUTO_COMPLETE_MINUTES", "5"))
@@ -52,6 +159,158 @@ func Load() (*Config, error) {
 		autoCompleteMinutes = 5
 	}
 
+	pollIntervalSeconds, err := strconv.Atoi(getEnv("WORKER_POLL_INTERVAL_SECONDS", "30"))
+	if err != nil {
+		pollIntervalSeconds = 30
+	}
+
+	lockTimeoutMinutes, err := strconv.Atoi(getEnv("JOB_LOCK_TIMEOUT_MINUTES", "5"))
+	if err != nil {
+		lockTimeoutMinutes = 5
+	}
+
+	maxAttempts, err := strconv.Atoi(getEnv("JOB_MAX_ATTEMPTS", "5"))
+	if err != nil {
+		maxAttempts = 5
+	}
+
+	retryBaseSeconds, err := strconv.Atoi(getEnv("JOB_RETRY_BASE_SECONDS", "30"))
+	if err != nil {
+		retryBaseSeconds = 30
+	}
+
+	jobRetentionDays, err := strconv.Atoi(getEnv("JOB_RETENTION_DAYS", "7"))
+	if err != nil {
+		jobRetentionDays = 7
+	}
+
+	subtaskMaxDepth, err := strconv.Atoi(getEnv("SUBTASK_MAX_DEPTH", "5"))
+	if err != nil {
+		subtaskMaxDepth = 5
+	}
+
+	invitationExpiryHours, err := strconv.Atoi(getEnv("ADMIN_INVITATION_EXPIRY_HOURS", "72"))
+	if err != nil {
+		invitationExpiryHours = 72
+	}
+
+	emailChangeExpiryHours, err := strconv.Atoi(getEnv("EMAIL_CHANGE_EXPIRY_HOURS", "24"))
+	if err != nil {
+		emailChangeExpiryHours = 24
+	}
+
+	resetExpiryMinutes, err := strconv.Atoi(getEnv("PASSWORD_RESET_EXPIRY_MINUTES", "60"))
+	if err != nil {
+		resetExpiryMinutes = 60
+	}
+
+	resetMaxPerAccount, err := strconv.Atoi(getEnv("PASSWORD_RESET_MAX_PER_ACCOUNT", "3"))
+	if err != nil {
+		resetMaxPerAccount = 3
+	}
+
+	resetMaxPerIP, err := strconv.Atoi(getEnv("PASSWORD_RESET_MAX_PER_IP", "10"))
+	if err != nil {
+		resetMaxPerIP = 10
+	}
+
+	verifyExpiryHours, err := strconv.Atoi(getEnv("EMAIL_VERIFICATION_EXPIRY_HOURS", "48"))
+	if err != nil {
+		verifyExpiryHours = 48
+	}
+
+	verifyResendSeconds, err := strconv.Atoi(getEnv("EMAIL_VERIFICATION_RESEND_SECONDS", "60"))
+	if err != nil {
+		verifyResendSeconds = 60
+	}
+
+	verifyMaxPerIP, err := strconv.Atoi(getEnv("EMAIL_VERIFICATION_MAX_PER_IP", "10"))
+	if err != nil {
+		verifyMaxPerIP = 10
+	}
+
+	verifyPolicy := domain.VerificationPolicy(getEnv("EMAIL_VERIFICATION", string(domain.VerifyOptional)))
+	if !verifyPolicy.IsValid() {
+		return nil, fmt.Errorf("invalid EMAIL_VERIFICATION %q: must be login, tasks or none", verifyPolicy)
+	}
+
+	challengeExpiryMinutes, err := strconv.Atoi(getEnv("MFA_CHALLENGE_EXPIRY_MINUTES", "5"))
+	if err != nil {
+		challengeExpiryMinutes = 5
+	}
+
+	mfaMaxAttempts, err := strconv.Atoi(getEnv("MFA_MAX_ATTEMPTS", "5"))
+	if err != nil {
+		mfaMaxAttempts = 5
+	}
+
+	mfaMaxFailures, err := strconv.Atoi(getEnv("MFA_MAX_FAILURES", "10"))
+	if err != nil {
+		mfaMaxFailures = 10
+	}
+
+	mfaLockMinutes, err := strconv.Atoi(getEnv("MFA_LOCK_MINUTES", "15"))
+	if err != nil {
+		mfaLockMinutes = 15
+	}
+
+	loginWindowMinutes, err := strconv.Atoi(getEnv("LOGIN_WINDOW_MINUTES", "15"))
+	if err != nil {
+		loginWindowMinutes = 15
+	}
+
+	loginDelayAfter, err := strconv.Atoi(getEnv("LOGIN_DELAY_AFTER", "3"))
+	if err != nil {
+		loginDelayAfter = 3
+	}
+
+	loginBaseDelaySeconds, err := strconv.Atoi(getEnv("LOGIN_BASE_DELAY_SECONDS", "1"))
+	if err != nil {
+		loginBaseDelaySeconds = 1
+	}
+
+	loginMaxDelaySeconds, err := strconv.Atoi(getEnv("LOGIN_MAX_DELAY_SECONDS", "30"))
+	if err != nil {
+		loginMaxDelaySeconds = 30
+	}
+
+	accountLockThreshold, err := strconv.Atoi(getEnv("LOGIN_ACCOUNT_LOCK_THRESHOLD", "10"))
+	if err != nil {
+		accountLockThreshold = 10
+	}
+
+	ipLockThreshold, err := strconv.Atoi(getEnv("LOGIN_IP_LOCK_THRESHOLD", "50"))
+	if err != nil {
+		ipLockThreshold = 50
+	}
+
+	loginLockMinutes, err := strconv.Atoi(getEnv("LOGIN_LOCK_MINUTES", "15"))
+	if err != nil {
+		loginLockMinutes = 15
+	}
+
+	jwtSecret := getEnv("JWT_SECRET", "default-secret-change-me")
+
+	mailDriver := getEnv("MAIL_DRIVER", "log")
+	if mailDriver != "log" && mailDriver != "file" && mailDriver != "smtp" {
+		return nil, fmt.Errorf("invalid MAIL_DRIVER %q: must be log, file or smtp", mailDriver)
+	}
+
+	retention := domain.AccountRetention(getEnv("ACCOUNT_RETENTION", string(domain.RetainAnonymized)))
+	if !retention.IsValid() {
+		return nil, fmt.Errorf("invalid ACCOUNT_RETENTION %q: must be anonymize or delete", retention)
+	}
+
+	onDelete := domain.SubtaskAction(getEnv("SUBTASK_ON_DELETE", string(domain.SubtaskBlock)))
+	if !onDelete.IsValid() {
+		return nil, fmt.Errorf("invalid SUBTASK_ON_DELETE %q: must be cascade, block or orphan", onDelete)
+	}
+
+	onComplete := domain.SubtaskAction(getEnv("SUBTASK_ON_COMPLETE", string(domain.SubtaskBlock)))
+	if !onComplete.IsValid() {
+		return nil, fmt.Errorf("invalid SUBTASK_ON_COMPLETE %q: must be cascade, block or orphan", onComplete)
+	}
+
 	return &Config{
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -62,14 +321,73 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "default-secret-change-me"),
-			Expiry: time.Duration(jwtExpiryHours) * time.Hour,
+			Secret:        jwtSecret,
+			Expiry:        time.Duration(jwtExpiryMinutes) * time.Minute,
+			RefreshExpiry: time.Duration(refreshExpiryHours) * time.Hour,
 		},
 		Server: ServerConfig{
 			Port: getEnv("SERVER_PORT", "3000"),
 		},
 		Worker: WorkerConfig{
 			AutoCompleteMinutes: autoCompleteMinutes,
+			PollInterval:        time.Duration(pollIntervalSeconds) * time.Second,
+			LockTimeout:         time.Duration(lockTimeoutMinutes) * time.Minute,
+			MaxAttempts:         maxAttempts,
+			RetryBaseDelay:      time.Duration(retryBaseSeconds) * time.Second,
+			JobRetention:        time.Duration(jobRetentionDays) * 24 * time.Hour,
+		},
+		Subtasks: SubtaskConfig{
+			MaxDepth:   subtaskMaxDepth,
+			OnDelete:   onDelete,
+			OnComplete: onComplete,
+		},
+		Admin: AdminConfig{
+			Email:            getEnv("BOOTSTRAP_ADMIN_EMAIL", ""),
+			Password:         getEnv("BOOTSTRAP_ADMIN_PASSWORD", ""),
+			InvitationExpiry: time.Duration(invitationExpiryHours) * time.Hour,
+		},
+		Account: AccountConfig{
+			Retention:         retention,
+			EmailChangeExpiry: time.Duration(emailChangeExpiryHours) * time.Hour,
+		},
+		Mail: MailConfig{
+			Driver:       mailDriver,
+			From:         getEnv("MAIL_FROM", "no-reply@localhost"),
+			File:         getEnv("MAIL_FILE", "mail.log"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
+		Reset: PasswordResetConfig{
+			Expiry:        time.Duration(resetExpiryMinutes) * time.Minute,
+			Window:        time.Hour,
+			MaxPerAccount: resetMaxPerAccount,
+			MaxPerIP:      resetMaxPerIP,
+		},
+		Verify: VerificationConfig{
+			Policy:         verifyPolicy,
+			Expiry:         time.Duration(verifyExpiryHours) * time.Hour,
+			ResendInterval: time.Duration(verifyResendSeconds) * time.Second,
+			Window:         time.Hour,
+			MaxPerIP:       verifyMaxPerIP,
+		},
+		MFA: MFAConfig{
+			Issuer:          getEnv("MFA_ISSUER", "Task Management"),
+			EncryptionKey:   getEnv("MFA_ENCRYPTION_KEY", jwtSecret),
+			ChallengeExpiry: time.Duration(challengeExpiryMinutes) * time.Minute,
+			MaxAttempts:     mfaMaxAttempts,
+			MaxFailures:     mfaMaxFailures,
+			LockDuration:    time.Duration(mfaLockMinutes) * time.Minute,
+		},
+		Login: LoginProtectionConfig{
+			Window:               time.Duration(loginWindowMinutes) * time.Minute,
+			DelayAfter:           loginDelayAfter,
+			BaseDelay:            time.Duration(loginBaseDelaySeconds) * time.Second,
+			MaxDelay:             time.Duration(loginMaxDelaySeconds) * time.Second,
+			AccountLockThreshold: accountLockThreshold,
+			IPLockThreshold:      ipLockThreshold,
+			LockDuration:         time.Duration(loginLockMinutes) * time.Minute,
 		},
 	}, nil
 }
@@ -86,4 +404,4 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// PersonalAccessTokenPrefix starts every personal access token, which tells
+// them apart from JWTs.
+const PersonalAccessTokenPrefix = "tmp_"
+
+// TokenScope is a permission granted to a personal access token.
+type TokenScope string
+
+const (
+	ScopeTasksRead TokenScope = "tasks:read"
+	// ScopeTasksWrite allows changing tasks, and reading them as well
+	ScopeTasksWrite TokenScope = "tasks:write"
+)
+
+func (s TokenScope) IsValid() bool {
+	return s == ScopeTasksRead || s == ScopeTasksWrite
+}
+
+// PersonalAccessToken lets scripts act as the user within the scopes, in the
+// organization OrgID. Token is only set in the response to creating it;
+// afterwards only Prefix, its first characters, is shown.
+type PersonalAccessToken struct {
+	ID         string       `json:"id"`
+	UserID     string       `json:"-"`
+	OrgID      *string      `json:"org_id"`
+	Name       string       `json:"name"`
+	Token      string       `json:"token,omitempty"`
+	Prefix     string       `json:"prefix"`
+	TokenHash  string       `json:"-"`
+	Scopes     []TokenScope `json:"scopes"`
+	ExpiresAt  *time.Time   `json:"expires_at"`
+	LastUsedAt *time.Time   `json:"last_used_at"`
+	RevokedAt  *time.Time   `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// HasScope reports whether the token grants the scope. Write access includes
+// read access.
+func (t *PersonalAccessToken) HasScope(scope TokenScope) bool {
+	for _, s := range t.Scopes {
+		if s == scope || (s == ScopeTasksWrite && scope == ScopeTasksRead) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAccessTokenRequest creates a token for the organization of the
+// session. Without ExpiresAt the token does not expire.
+type CreateAccessTokenRequest struct {
+	Name      string       `json:"name"`
+	Scopes    []TokenScope `json:"scopes"`
+	ExpiresAt *time.Time   `json:"expires_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// AdminInvitation invites someone, by email, to become a platform admin.
+// Token is only set in the response to the invitation; afterwards only its
+// hash is known.
+type AdminInvitation struct {
+	ID         string     `json:"id"`
+	Email      string     `json:"email"`
+	Token      string     `json:"token,omitempty"`
+	TokenHash  string     `json:"-"`
+	InvitedBy  *string    `json:"invited_by,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type InviteAdminRequest struct {
+	Email string `json:"email"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token"`
+}
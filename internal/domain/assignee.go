@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// MaxAssignees limits how many users a task can be assigned to.
+const MaxAssignees = 20
+
+type AssignmentAction string
+
+const (
+	AssignmentAdded   AssignmentAction = "assigned"
+	AssignmentRemoved AssignmentAction = "unassigned"
+)
+
+// Assignee is a user a task is assigned to. AssignedBy is nil if the user who
+// made the assignment has since been deleted.
+type Assignee struct {
+	UserID     string    `json:"user_id"`
+	AssignedBy *string   `json:"assigned_by,omitempty"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// AssignmentChange records one user being assigned to or removed from a task.
+type AssignmentChange struct {
+	ID        string           `json:"id"`
+	TaskID    string           `json:"task_id"`
+	UserID    string           `json:"user_id"`
+	Action    AssignmentAction `json:"action"`
+	ChangedBy *string          `json:"changed_by,omitempty"`
+	ChangedAt time.Time        `json:"changed_at"`
+}
+
+type ReassignRequest struct {
+	AssigneeIDs []string `json:"assignee_ids"`
+}
@@ -0,0 +1,161 @@
+package domain
+
+import "errors"
+
+// Error kinds. Every *Error wraps exactly one of these, so callers can branch
+// on the kind with errors.Is without knowing the specific error.
+var (
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// FieldError describes a problem with a single request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an error meant to be shown to API clients. Code is a stable,
+// machine-readable identifier; Message is the human-readable detail.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  []FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NewValidationError(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message, Fields: fields}
+}
+
+func NewUnauthorizedError(code, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+func NewForbiddenError(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+func NewNotFoundError(code, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+func NewRateLimitError(code, message string) *Error {
+	return &Error{Kind: ErrRateLimited, Code: code, Message: message}
+}
+
+// FieldValidationError reports a single invalid field.
+func FieldValidationError(code, field, message string) *Error {
+	return NewValidationError(code, message, FieldError{Field: field, Message: message})
+}
+
+var (
+	ErrInvalidRequestBody = NewValidationError("invalid_request_body", "invalid request body")
+
+	ErrUserExists          = NewConflictError("user_exists", "user already exists")
+	ErrInvalidCredentials  = NewUnauthorizedError("invalid_credentials", "invalid credentials")
+	ErrInvalidToken        = NewUnauthorizedError("invalid_token", "invalid or expired token")
+	ErrTokenRevoked        = NewUnauthorizedError("token_revoked", "token has been revoked")
+	ErrInvalidRefreshToken = NewUnauthorizedError("invalid_refresh_token", "invalid refresh token")
+	ErrRefreshTokenReused  = NewUnauthorizedError("refresh_token_reused", "refresh token reuse detected")
+	ErrAdminRequired       = NewForbiddenError("admin_required", "admin access required")
+
+	ErrUserNotFound       = NewNotFoundError("user_not_found", "user not found")
+	ErrLastAdmin          = NewConflictError("last_admin", "at least one admin is required")
+	ErrAlreadyAdmin       = NewConflictError("already_admin", "user is already an admin")
+	ErrInvitationNotFound = NewNotFoundError("invitation_not_found", "invitation not found")
+	ErrInvalidInvitation  = NewValidationError("invalid_invitation", "invitation is invalid or expired")
+
+	ErrAccountDisabled         = NewForbiddenError("account_disabled", "account is disabled")
+	ErrPasswordResetRequired   = NewForbiddenError("password_reset_required", "a new password is required; log in again with new_password")
+	ErrOwnAccount              = NewConflictError("own_account", "admins cannot disable or delete their own account")
+	ErrInvalidUserRole         = FieldValidationError("invalid_role", "role", "role must be user or admin")
+	ErrInvalidTaskDisposition  = FieldValidationError("invalid_task_disposition", "tasks", "tasks must be delete or reassign")
+	ErrInvalidReassignTarget   = FieldValidationError("invalid_reassign_to", "reassign_to", "reassign_to must be another enabled user")
+	ErrInvalidUserStatusFilter = FieldValidationError("invalid_status", "status", "status must be active or disabled")
+
+	ErrWrongPassword        = FieldValidationError("wrong_password", "password", "password is incorrect")
+	ErrWrongCurrentPassword = FieldValidationError("wrong_password", "current_password", "current password is incorrect")
+	ErrEmailUnchanged       = FieldValidationError("email_unchanged", "email", "this is already the email address of the account")
+	ErrInvalidEmailChange   = NewValidationError("invalid_email_change", "email change token is invalid or expired")
+
+	ErrInvalidPasswordReset = NewValidationError("invalid_password_reset", "password reset token is invalid or expired")
+	ErrTooManyRequests      = NewRateLimitError("too_many_requests", "too many requests, try again later")
+
+	ErrEmailNotVerified    = NewForbiddenError("email_not_verified", "email address is not verified")
+	ErrInvalidVerification = NewValidationError("invalid_verification", "verification token is invalid or expired")
+
+	ErrMFAAlreadyEnabled   = NewConflictError("mfa_enabled", "two-factor authentication is already enabled")
+	ErrMFANotEnabled       = NewConflictError("mfa_not_enabled", "two-factor authentication is not enabled")
+	ErrMFANotEnrolled      = NewConflictError("mfa_not_enrolled", "start the two-factor enrolment first")
+	ErrInvalidMFACode      = FieldValidationError("invalid_mfa_code", "code", "code is invalid")
+	ErrInvalidMFAChallenge = NewUnauthorizedError("invalid_mfa_challenge", "challenge token is invalid or expired")
+	ErrMFALocked           = NewRateLimitError("mfa_locked", "too many wrong codes, try again later")
+
+	ErrLoginLocked              = NewRateLimitError("login_locked", "too many failed logins, try again later")
+	ErrLoginThrottled           = NewRateLimitError("login_throttled", "too many failed logins, wait a moment before trying again")
+	ErrInvalidSecurityEventType = FieldValidationError("invalid_type", "type", "type must be account_locked or ip_locked")
+
+	ErrAccessTokenNotFound   = NewNotFoundError("access_token_not_found", "access token not found")
+	ErrInvalidTokenScope     = FieldValidationError("invalid_scope", "scopes", "scopes must be tasks:read or tasks:write")
+	ErrTokenScopesRequired   = FieldValidationError("scopes_required", "scopes", "at least one scope is required")
+	ErrInvalidTokenExpiry    = FieldValidationError("invalid_expires_at", "expires_at", "expires_at must be in the future")
+	ErrAccessTokenNotAllowed = NewForbiddenError("access_token_not_allowed", "personal access tokens cannot be used for this endpoint")
+	ErrInsufficientScope     = NewForbiddenError("insufficient_scope", "the access token lacks the required scope")
+
+	ErrTaskNotFound        = NewNotFoundError("task_not_found", "task not found")
+	ErrTaskAccessDenied    = NewForbiddenError("task_access_denied", "unauthorized access")
+	ErrAssigneeStatusOnly  = NewForbiddenError("assignee_status_only", "assignees may only change the status")
+	ErrAssigneeNotFound    = FieldValidationError("assignee_not_found", "assignee_ids", "assignee is not a member of the organization")
+	ErrTooManyAssignees    = FieldValidationError("too_many_assignees", "assignee_ids", "too many assignees")
+	ErrAssigneesRequired   = FieldValidationError("assignees_required", "assignee_ids", "at least one assignee is required")
+	ErrInvalidStatus       = FieldValidationError("invalid_status", "status", "invalid status")
+	ErrInvalidPriority     = FieldValidationError("invalid_priority", "priority", "invalid priority")
+	ErrInvalidAutoComplete = FieldValidationError("invalid_auto_complete", "auto_complete", "invalid auto_complete policy")
+	ErrInvalidRemindAt     = FieldValidationError("invalid_remind_at", "remind_at", "remind_at must not be after due_at")
+
+	ErrParentNotFound     = FieldValidationError("parent_not_found", "parent_id", "parent task not found")
+	ErrSubtaskCycle       = FieldValidationError("subtask_cycle", "parent_id", "a task cannot be moved under itself or one of its subtasks")
+	ErrSubtaskTooDeep     = FieldValidationError("subtask_too_deep", "parent_id", "subtask depth limit exceeded")
+	ErrTaskHasSubtasks    = NewConflictError("task_has_subtasks", "task has subtasks")
+	ErrSubtasksIncomplete = NewConflictError("subtasks_incomplete", "task has incomplete subtasks")
+	ErrSubtaskAccess      = NewForbiddenError("subtask_access_denied", "not allowed to change every subtask of this task")
+
+	ErrBlockerNotFound = NewNotFoundError("blocking_task_not_found", "blocking task not found")
+	ErrDependencyCycle = NewConflictError("dependency_cycle", "dependency would create a cycle")
+	ErrTaskBlocked     = NewConflictError("task_blocked", "task is blocked by open tasks")
+
+	ErrWorkflowNotFound = NewNotFoundError("workflow_not_found", "workflow not found")
+	ErrWorkflowExists   = NewConflictError("workflow_exists", "workflow already exists")
+	ErrWorkflowInUse    = NewConflictError("workflow_in_use", "workflow is used by tasks")
+	ErrWorkflowBuiltIn  = NewForbiddenError("workflow_built_in", "the default workflow cannot be changed")
+
+	ErrOrgNotFound       = NewNotFoundError("org_not_found", "organization not found")
+	ErrNoOrganization    = NewForbiddenError("no_organization", "no organization selected")
+	ErrOrgRoleRequired   = NewForbiddenError("org_role_required", "insufficient organization role")
+	ErrInvalidOrgRole    = FieldValidationError("invalid_org_role", "role", "role must be owner, admin, member or viewer")
+	ErrOrgNameRequired   = FieldValidationError("name_required", "name", "name is required")
+	ErrMemberNotFound    = NewNotFoundError("member_not_found", "member not found")
+	ErrMemberExists      = NewConflictError("member_exists", "user is already a member")
+	ErrMemberUserUnknown = FieldValidationError("user_not_found", "email", "no user with this email")
+	ErrLastOwner         = NewConflictError("last_owner", "an organization needs at least one owner")
+
+	ErrLabelNotFound = NewNotFoundError("label_not_found", "label not found")
+	ErrLabelExists   = NewConflictError("label_exists", "label already exists")
+)
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type TaskEventType string
+
+const (
+	EventTaskReminder TaskEventType = "task.reminder"
+	EventTaskOverdue  TaskEventType = "task.overdue"
+)
+
+// TaskEvent is emitted by the worker when something time based happens to a
+// task, e.g. its reminder time or due date passes.
+type TaskEvent struct {
+	Type       TaskEventType `json:"type"`
+	TaskID     string        `json:"task_id"`
+	UserID     string        `json:"user_id"`
+	Title      string        `json:"title"`
+	DueAt      *time.Time    `json:"due_at,omitempty"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type JobType string
+
+const (
+	JobAutoCompleteTask JobType = "auto_complete_task"
+	JobTaskReminder     JobType = "task_reminder"
+	JobTaskOverdue      JobType = "task_overdue"
+)
+
+type JobStatus string
+
+const (
+	JobQueued  JobStatus = "queued"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobDead    JobStatus = "dead"
+)
+
+// Job is a unit of background work persisted in the jobs table. Jobs become
+// eligible once RunAt has passed and are retried with backoff until
+// MaxAttempts is reached, after which they are moved to the dead state.
+type Job struct {
+	ID          string     `json:"id"`
+	Type        JobType    `json:"type"`
+	TaskID      string     `json:"task_id"`
+	Status      JobStatus  `json:"status"`
+	RunAt       time.Time  `json:"run_at"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	LastError   *string    `json:"last_error,omitempty"`
+	LockedAt    *time.Time `json:"locked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
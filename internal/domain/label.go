@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+type LabelMatch string
+
+const (
+	LabelMatchAny LabelMatch = "any"
+	LabelMatchAll LabelMatch = "all"
+)
+
+type Label struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateLabelRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type UpdateLabelRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+func (m LabelMatch) IsValid() bool {
+	return m == LabelMatchAny || m == LabelMatchAll
+}
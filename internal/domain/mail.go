@@ -0,0 +1,8 @@
+package domain
+
+// Email is a plain-text message to a single recipient.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "time"
+
+// RecoveryCodeCount is the number of recovery codes a user gets at a time.
+const RecoveryCodeCount = 10
+
+// UserMFA is a user's TOTP second factor. Secret is encrypted at rest and
+// only decrypted to check codes.
+type UserMFA struct {
+	UserID         string
+	Secret         string
+	ConfirmedAt    *time.Time
+	LastUsedStep   int64
+	FailedAttempts int
+	LastFailureAt  *time.Time
+	CreatedAt      time.Time
+}
+
+// RecoveryCode can be used once instead of a TOTP code. Only its hash is
+// stored.
+type RecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAChallenge is a login waiting for the second factor. A limited number of
+// codes may be tried with it before it expires. NewPasswordHash holds the
+// password chosen by a login that had to replace it, which is only stored
+// once the second factor is checked.
+type MFAChallenge struct {
+	ID              string
+	UserID          string
+	TokenHash       string
+	NewPasswordHash *string
+	Attempts        int
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+}
+
+// MFAStatus describes the second factor of a user.
+type MFAStatus struct {
+	Enabled           bool       `json:"enabled"`
+	ConfirmedAt       *time.Time `json:"confirmed_at,omitempty"`
+	RecoveryCodesLeft int        `json:"recovery_codes_left"`
+}
+
+// MFAEnrollment is returned when enrolment starts. URI is the otpauth://
+// URI to show as a QR code; Secret is for manual entry.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// RecoveryCodes are shown once, when they are generated.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAChallengeResponse is returned by the password step of a login when the
+// user has a second factor.
+type MFAChallengeResponse struct {
+	MFARequired    bool      `json:"mfa_required"`
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type EnrollMFARequest struct {
+	Password string `json:"password"`
+}
+
+type ConfirmMFARequest struct {
+	Code string `json:"code"`
+}
+
+// DisableMFARequest takes a TOTP code or a recovery code in Code.
+type DisableMFARequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	Password string `json:"password"`
+}
+
+// VerifyMFARequest completes a login with either a TOTP code or a recovery
+// code.
+type VerifyMFARequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
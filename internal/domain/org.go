@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+// OrgRole is a user's role within an organization. Unlike UserRole, which
+// distinguishes platform operators, it governs access to the organization's
+// data.
+type OrgRole string
+
+const (
+	OrgRoleOwner  OrgRole = "owner"
+	OrgRoleAdmin  OrgRole = "admin"
+	OrgRoleMember OrgRole = "member"
+	OrgRoleViewer OrgRole = "viewer"
+)
+
+// orgRoleRanks orders the roles from least to most privileged.
+var orgRoleRanks = map[OrgRole]int{
+	OrgRoleViewer: 1,
+	OrgRoleMember: 2,
+	OrgRoleAdmin:  3,
+	OrgRoleOwner:  4,
+}
+
+// PersonalOrgName is the name of the organization created for every new user.
+const PersonalOrgName = "Personal"
+
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedBy *string   `json:"created_by,omitempty"`
+	Role      OrgRole   `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Membership grants a user a role in an organization. Email is filled in when
+// members are listed.
+type Membership struct {
+	OrgID     string    `json:"org_id"`
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email,omitempty"`
+	Role      OrgRole   `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateOrgRequest struct {
+	Name string `json:"name"`
+}
+
+type AddMemberRequest struct {
+	Email string  `json:"email"`
+	Role  OrgRole `json:"role"`
+}
+
+type UpdateMemberRequest struct {
+	Role OrgRole `json:"role"`
+}
+
+type SwitchOrgRequest struct {
+	OrgID string `json:"org_id"`
+}
+
+func (r OrgRole) IsValid() bool {
+	_, ok := orgRoleRanks[r]
+	return ok
+}
+
+// AtLeast reports whether the role has the privileges of min.
+func (r OrgRole) AtLeast(min OrgRole) bool {
+	return orgRoleRanks[r] >= orgRoleRanks[min]
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 100
+)
+
+var ErrInvalidCursor = FieldValidationError("invalid_cursor", "cursor", "invalid cursor")
+
+// TaskCursor marks the position after the last task of a page. It records the
+// sort specification it was issued for together with the last task's sort
+// values and ID, so the next page can continue with a keyset condition.
+type TaskCursor struct {
+	Sort   string    `json:"s"`
+	Values []*string `json:"v"`
+	ID     string    `json:"id"`
+}
+
+// TaskPage is a page of tasks as returned by the list endpoint.
+type TaskPage struct {
+	Data       []Task `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	TotalCount *int   `json:"total_count,omitempty"`
+}
+
+// NewTaskCursor builds the cursor pointing after task for the given sort.
+func NewTaskCursor(sort []SortKey, task *Task) *TaskCursor {
+	cursor := &TaskCursor{Sort: FormatTaskSort(sort), ID: task.ID}
+	for _, key := range sort {
+		cursor.Values = append(cursor.Values, task.SortValue(key.Field))
+	}
+	return cursor
+}
+
+func (c *TaskCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeTaskCursor parses an opaque cursor token and checks that it was
+// issued for the same sort specification.
+func DecodeTaskCursor(token string, sort []SortKey) (*TaskCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor TaskCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.ID == "" || cursor.Sort != FormatTaskSort(sort) || len(cursor.Values) != len(sort) {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// FormatTaskSort is the inverse of ParseTaskSort.
+func FormatTaskSort(sort []SortKey) string {
+	parts := make([]string, len(sort))
+	for i, key := range sort {
+		parts[i] = key.Field
+		if key.Desc {
+			parts[i] = "-" + key.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// SortValue returns the value the task is sorted by for field, in the form
+// it is compared in SQL. Nil means NULL.
+func (t *Task) SortValue(field string) *string {
+	var value string
+	switch field {
+	case "created_at":
+		value = t.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		value = t.UpdatedAt.Format(time.RFC3339Nano)
+	case "due_at":
+		if t.DueAt == nil {
+			return nil
+		}
+		value = t.DueAt.Format(time.RFC3339Nano)
+	case "priority":
+		value = strconv.Itoa(t.Priority.Rank())
+	case "title":
+		value = t.Title
+	case "status":
+		value = string(t.Status)
+	case "rank":
+		if t.Search == nil {
+			return nil
+		}
+		// ts_rank returns a real; format it so it parses back exactly
+		value = strconv.FormatFloat(t.Search.Rank, 'g', -1, 32)
+	default:
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTaskCursor(t *testing.T) {
+	created := time.Date(2025, 1, 22, 10, 30, 0, 123456000, time.UTC)
+	due := created.Add(48 * time.Hour)
+	task := &Task{ID: "task-1", Title: "Deploy", Priority: PriorityHigh, CreatedAt: created, DueAt: &due}
+	noDue := &Task{ID: "task-2", Title: "Write notes", Priority: PriorityLow, CreatedAt: created}
+
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name   string
+		sort   []SortKey
+		task   *Task
+		values []*string
+	}{
+		{"default sort", DefaultTaskSort, task, []*string{str(created.Format(time.RFC3339Nano))}},
+		{"priority rank", []SortKey{{Field: "priority", Desc: true}, {Field: "title"}}, task, []*string{str("3"), str("Deploy")}},
+		{"due date", []SortKey{{Field: "due_at"}}, task, []*string{str(due.Format(time.RFC3339Nano))}},
+		{"missing due date is null", []SortKey{{Field: "due_at"}}, noDue, []*string{nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := NewTaskCursor(tt.sort, tt.task)
+			if !reflect.DeepEqual(cursor.Values, tt.values) {
+				t.Errorf("Values = %v, want %v", cursor.Values, tt.values)
+			}
+
+			decoded, err := DecodeTaskCursor(cursor.Encode(), tt.sort)
+			if err != nil {
+				t.Fatalf("DecodeTaskCursor() error = %v", err)
+			}
+			if !reflect.DeepEqual(decoded, cursor) {
+				t.Errorf("DecodeTaskCursor() = %+v, want %+v", decoded, cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeTaskCursorInvalid(t *testing.T) {
+	sort := []SortKey{{Field: "priority", Desc: true}, {Field: "title"}}
+	valid := NewTaskCursor(sort, &Task{ID: "task-1", Title: "Deploy"}).Encode()
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		token string
+		sort  []SortKey
+	}{
+		{"not base64", "!!!", sort},
+		{"not json", encode("cursor"), sort},
+		{"missing id", encode(`{"s":"-priority,title","v":["3","Deploy"]}`), sort},
+		{"wrong number of values", encode(`{"s":"-priority,title","v":["3"],"id":"task-1"}`), sort},
+		{"issued for another sort", valid, []SortKey{{Field: "priority"}, {Field: "title"}}},
+		{"issued for fewer keys", valid, []SortKey{{Field: "priority", Desc: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeTaskCursor(tt.token, tt.sort); err != ErrInvalidCursor {
+				t.Errorf("DecodeTaskCursor() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// PasswordReset is a token that lets the owner of an email address choose a
+// new password. Only the hash of the token is stored.
+type PasswordReset struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
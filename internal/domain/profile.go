@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// UpdateProfileRequest changes the given settings. An empty display name or
+// avatar URL removes it.
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name"`
+	Timezone    *string `json:"timezone"`
+	Locale      *string `json:"locale"`
+	AvatarURL   *string `json:"avatar_url"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// EmailChange is a requested change of a user's email address, waiting for
+// the new address to be confirmed.
+type EmailChange struct {
+	UserID    string
+	NewEmail  string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// AccountRetention decides what happens when users delete their account.
+type AccountRetention string
+
+const (
+	// RetainAnonymized keeps the account row and the tasks the user created,
+	// with the personal data removed
+	RetainAnonymized AccountRetention = "anonymize"
+	// RetainNothing removes the account together with the tasks the user
+	// created
+	RetainNothing AccountRetention = "delete"
+)
+
+func (r AccountRetention) IsValid() bool {
+	return r == RetainAnonymized || r == RetainNothing
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	maxSearchLength = 200
+	maxSearchTerms  = 10
+)
+
+var ErrInvalidSearch = FieldValidationError("invalid_search", "q", "search query must contain at least one word")
+
+// SearchMatch describes why a task matched a search query. Title and Snippet
+// are HTML-escaped, with the matches highlighted with <mark> tags.
+type SearchMatch struct {
+	Rank    float64 `json:"rank"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+}
+
+// DefaultSearchSort orders search results by relevance.
+var DefaultSearchSort = []SortKey{{Field: "rank", Desc: true}}
+
+// ParseSearchQuery turns user input into a Postgres tsquery expression.
+// Words are combined with AND, "quoted text" matches as a phrase and a
+// trailing * matches words by prefix, e.g.
+//
+//	deploy "release notes" stag*  =>  deploy & release <-> notes & stag:*
+//
+// Everything except letters and digits is treated as a separator, so the
+// result never contains tsquery operators supplied by the user.
+func ParseSearchQuery(q string) (string, error) {
+	if len(q) > maxSearchLength {
+		return "", FieldValidationError("invalid_search", "q", "search query is too long")
+	}
+
+	var terms []string
+	for i, segment := range strings.Split(q, `"`) {
+		// Odd segments are inside quotes
+		if i%2 == 1 {
+			if words := searchWords(segment); len(words) > 0 {
+				terms = append(terms, strings.Join(words, " <-> "))
+			}
+			continue
+		}
+
+		for _, field := range strings.Fields(segment) {
+			prefix := strings.HasSuffix(field, "*")
+			words := searchWords(field)
+			if len(words) == 0 {
+				continue
+			}
+			if prefix {
+				words[len(words)-1] += ":*"
+			}
+			terms = append(terms, strings.Join(words, " <-> "))
+		}
+	}
+
+	if len(terms) == 0 {
+		return "", ErrInvalidSearch
+	}
+	if len(terms) > maxSearchTerms {
+		return "", FieldValidationError("invalid_search", "q", "too many search terms")
+	}
+	return strings.Join(terms, " & "), nil
+}
+
+func searchWords(s string) []string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return words
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		q        string
+		want     string
+		wantCode string
+	}{
+		{"single word", "deploy", "deploy", ""},
+		{"words are combined with and", "deploy staging", "deploy & staging", ""},
+		{"lowercased", "Deploy STAGING", "deploy & staging", ""},
+		{"phrase", `"release notes"`, "release <-> notes", ""},
+		{"prefix", "stag*", "stag:*", ""},
+		{"mixed", `deploy "release notes" stag*`, "deploy & release <-> notes & stag:*", ""},
+		{"punctuation splits words", "e-mail", "e <-> mail", ""},
+		{"operators are separators", "a & !b | (c)", "a & b & c", ""},
+		{"unicode letters", "Überprüfung", "überprüfung", ""},
+		{"unclosed quote", `"release notes`, "release <-> notes", ""},
+		{"empty", "", "", "invalid_search"},
+		{"only punctuation", `!& "" *`, "", "invalid_search"},
+		{"too many terms", "a b c d e f g h i j k", "", "invalid_search"},
+		{"too long", strings.Repeat("a", 201), "", "invalid_search"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSearchQuery(tt.q)
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("ParseSearchQuery(%q) error = %q, want %q", tt.q, code, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSearchQuery(%q) = %q, want %q", tt.q, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// AttemptKind says what a login attempt counter is kept for.
+type AttemptKind string
+
+const (
+	AttemptsByAccount AttemptKind = "account"
+	AttemptsByIP      AttemptKind = "ip"
+)
+
+// LoginAttempts counts the failed logins for an account email or client IP
+// since WindowStart.
+type LoginAttempts struct {
+	Kind          AttemptKind
+	Key           string
+	Failures      int
+	WindowStart   time.Time
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// SecurityEventType names an entry of the security audit trail.
+type SecurityEventType string
+
+const (
+	EventAccountLocked SecurityEventType = "account_locked"
+	EventIPLocked      SecurityEventType = "ip_locked"
+)
+
+func (t SecurityEventType) IsValid() bool {
+	return t == EventAccountLocked || t == EventIPLocked
+}
+
+// SecurityEvent is an entry of the security audit trail. UserID is only set
+// when the email belongs to an account.
+type SecurityEvent struct {
+	ID          string            `json:"id"`
+	Type        SecurityEventType `json:"type"`
+	UserID      *string           `json:"user_id"`
+	Email       *string           `json:"email"`
+	IP          *string           `json:"ip"`
+	LockedUntil *time.Time        `json:"locked_until,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// SecurityEventFilter selects events for admins, newest first.
+type SecurityEventFilter struct {
+	Type   *SecurityEventType
+	UserID string
+	Email  string
+	IP     string
+	Since  *time.Time
+	Limit  int
+	Offset int
+}
+
+type SecurityEventPage struct {
+	Data       []SecurityEvent `json:"data"`
+	HasMore    bool            `json:"has_more"`
+	TotalCount int             `json:"total_count"`
+}
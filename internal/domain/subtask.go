@@ -0,0 +1,35 @@
+package domain
+
+// SubtaskAction controls what happens to the subtasks of a task that is
+// deleted or completed.
+type SubtaskAction string
+
+const (
+	// SubtaskCascade applies the same change to all subtasks.
+	SubtaskCascade SubtaskAction = "cascade"
+	// SubtaskBlock rejects the change while open subtasks remain.
+	SubtaskBlock SubtaskAction = "block"
+	// SubtaskOrphan detaches the affected subtasks to the top level.
+	SubtaskOrphan SubtaskAction = "orphan"
+)
+
+func (a SubtaskAction) IsValid() bool {
+	switch a {
+	case SubtaskCascade, SubtaskBlock, SubtaskOrphan:
+		return true
+	}
+	return false
+}
+
+// SubtaskProgress rolls up the status of a task's direct subtasks.
+type SubtaskProgress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+}
+
+// TaskNode is a task together with its subtasks, as returned by the tree
+// endpoint.
+type TaskNode struct {
+	Task
+	Children []*TaskNode `json:"children"`
+}
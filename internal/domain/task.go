@@ -1,9 +1,12 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
+// TaskStatus is a status of the task's workflow. The constants are the
+// statuses of the default workflow.
 type TaskStatus string
 
 const (
@@ -12,37 +15,257 @@ const (
 	StatusCompleted  TaskStatus = "completed"
 )
 
+type TaskPriority string
+
+const (
+	PriorityUrgent TaskPriority = "urgent"
+	PriorityHigh   TaskPriority = "high"
+	PriorityMedium TaskPriority = "medium"
+	PriorityLow    TaskPriority = "low"
+)
+
+type AutoCompleteMode string
+
+const (
+	AutoCompleteNever        AutoCompleteMode = "never"
+	AutoCompleteAfter        AutoCompleteMode = "after"
+	AutoCompleteAt           AutoCompleteMode = "at"
+	AutoCompleteSubtasksDone AutoCompleteMode = "subtasks_done"
+)
+
+// AutoCompletePolicy controls when the worker completes a task on its own.
+// AfterMinutes is used by the "after" mode and At by the "at" mode. Tasks
+// without a policy fall back to the global AUTO_COMPLETE_MINUTES delay.
+type AutoCompletePolicy struct {
+	Mode         AutoCompleteMode `json:"mode"`
+	AfterMinutes *int             `json:"after_minutes,omitempty"`
+	At           *time.Time       `json:"at,omitempty"`
+}
+
+// DueWindow selects tasks by due date relative to the current time.
+type DueWindow string
+
+const (
+	DueOverdue  DueWindow = "overdue"
+	DueToday    DueWindow = "today"
+	DueThisWeek DueWindow = "week"
+)
+
+// SortKey is one key of a task sort specification.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// taskSortFields are the fields a task list may be sorted by.
+var taskSortFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"due_at":     true,
+	"priority":   true,
+	"title":      true,
+	"status":     true,
+	"rank":       true, // search relevance, only with a search query
+}
+
+const maxSortKeys = 5
+
+// Task belongs to an organization (OrgID), is owned by the user who created
+// it (UserID) and may be assigned to other members.
 type Task struct {
-	ID          string     `json:"id"`
-	UserID      string     `json:"user_id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      TaskStatus `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID             string              `json:"id"`
+	OrgID          string              `json:"org_id"`
+	UserID         string              `json:"user_id"`
+	ParentID       *string             `json:"parent_id,omitempty"`
+	WorkflowID     *string             `json:"workflow_id,omitempty"`
+	Title          string              `json:"title"`
+	Description    string              `json:"description"`
+	Status         TaskStatus          `json:"status"`
+	StatusCategory StatusCategory      `json:"status_category"`
+	Priority       TaskPriority        `json:"priority"`
+	AutoComplete   *AutoCompletePolicy `json:"auto_complete,omitempty"`
+	DueAt          *time.Time          `json:"due_at,omitempty"`
+	RemindAt       *time.Time          `json:"remind_at,omitempty"`
+	RemindedAt     *time.Time          `json:"reminded_at,omitempty"`
+	OverdueAt      *time.Time          `json:"overdue_at,omitempty"`
+	Labels         []Label             `json:"labels"`
+	Assignees      []Assignee          `json:"assignees"`
+	Progress       *SubtaskProgress    `json:"progress,omitempty"`
+	Blocked        bool                `json:"blocked"`
+	Search         *SearchMatch        `json:"search,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
 }
 
 type CreateTaskRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	Priority     TaskPriority        `json:"priority,omitempty"`
+	AutoComplete *AutoCompletePolicy `json:"auto_complete,omitempty"`
+	DueAt        *time.Time          `json:"due_at,omitempty"`
+	RemindAt     *time.Time          `json:"remind_at,omitempty"`
+	ParentID     *string             `json:"parent_id,omitempty"`
+	WorkflowID   *string             `json:"workflow_id,omitempty"`
+	AssigneeIDs  []string            `json:"assignee_ids,omitempty"`
 }
 
+// UpdateTaskRequest holds the fields to change. An empty ParentID moves the
+// task to the top level.
 type UpdateTaskRequest struct {
-	Title       *string     `json:"title,omitempty"`
-	Description *string     `json:"description,omitempty"`
-	Status      *TaskStatus `json:"status,omitempty"`
+	Title        *string             `json:"title,omitempty"`
+	Description  *string             `json:"description,omitempty"`
+	Status       *TaskStatus         `json:"status,omitempty"`
+	Priority     *TaskPriority       `json:"priority,omitempty"`
+	AutoComplete *AutoCompletePolicy `json:"auto_complete,omitempty"`
+	DueAt        *time.Time          `json:"due_at,omitempty"`
+	RemindAt     *time.Time          `json:"remind_at,omitempty"`
+	ParentID     *string             `json:"parent_id,omitempty"`
 }
 
 type TaskFilter struct {
-	Status *TaskStatus
-	Limit  int
-	Offset int
+	AssigneeID string
+	CreatedBy  string
+	Status     *TaskStatus
+	Category   *StatusCategory
+	Due        *DueWindow
+	Labels     []string
+	LabelMatch LabelMatch
+	Blocked    *bool
+	Search     string // tsquery expression, see ParseSearchQuery
+	Sort       []SortKey
+	Cursor     *TaskCursor
+	WithTotal  bool
+	Limit      int
+	Offset     int
+}
+
+func (p AutoCompletePolicy) IsValid() bool {
+	switch p.Mode {
+	case AutoCompleteNever, AutoCompleteSubtasksDone:
+		return p.AfterMinutes == nil && p.At == nil
+	case AutoCompleteAfter:
+		return p.AfterMinutes != nil && *p.AfterMinutes > 0 && p.At == nil
+	case AutoCompleteAt:
+		return p.At != nil && p.AfterMinutes == nil
+	}
+	return false
+}
+
+// AutoCompleteAt returns when the worker should complete the task, using
+// defaultDelay for tasks without a policy. It reports false for policies
+// that are not time based.
+func (t *Task) AutoCompleteAt(defaultDelay time.Duration) (time.Time, bool) {
+	if t.AutoComplete == nil {
+		return t.CreatedAt.Add(defaultDelay), true
+	}
+	switch t.AutoComplete.Mode {
+	case AutoCompleteAfter:
+		return t.CreatedAt.Add(time.Duration(*t.AutoComplete.AfterMinutes) * time.Minute), true
+	case AutoCompleteAt:
+		return *t.AutoComplete.At, true
+	}
+	return time.Time{}, false
+}
+
+func (w DueWindow) IsValid() bool {
+	switch w {
+	case DueOverdue, DueToday, DueThisWeek:
+		return true
+	}
+	return false
+}
+
+// Range returns the due_at interval [from, to) covered by the window. Weeks
+// start on Monday. The overdue window has no lower bound and ends at now.
+func (w DueWindow) Range(now time.Time) (from *time.Time, to time.Time) {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch w {
+	case DueToday:
+		return &startOfDay, startOfDay.AddDate(0, 0, 1)
+	case DueThisWeek:
+		offset := (int(now.Weekday()) + 6) % 7
+		startOfWeek := startOfDay.AddDate(0, 0, -offset)
+		return &startOfWeek, startOfWeek.AddDate(0, 0, 7)
+	}
+	return nil, now
+}
+
+// OnlyStatus reports whether the request changes nothing but the status.
+func (r *UpdateTaskRequest) OnlyStatus() bool {
+	return r.Title == nil && r.Description == nil && r.Priority == nil && r.AutoComplete == nil &&
+		r.DueAt == nil && r.RemindAt == nil && r.ParentID == nil
+}
+
+// IsClosed reports whether the task is in a closed status of its workflow.
+func (t *Task) IsClosed() bool {
+	return t.StatusCategory == CategoryClosed
+}
+
+// NeedsReminder reports whether the worker still has to send a reminder.
+func (t *Task) NeedsReminder() bool {
+	return t.RemindAt != nil && t.RemindedAt == nil && !t.IsClosed()
+}
+
+// NeedsOverdueMark reports whether the task is past due without having been
+// flagged yet.
+func (t *Task) NeedsOverdueMark() bool {
+	return t.DueAt != nil && t.OverdueAt == nil && !t.IsClosed()
 }
 
-func (ts TaskStatus) IsValid() bool {
-	switch ts {
-	case StatusPending, StatusInProgress, StatusCompleted:
+func (p TaskPriority) IsValid() bool {
+	switch p {
+	case PriorityUrgent, PriorityHigh, PriorityMedium, PriorityLow:
 		return true
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// Rank orders priorities by importance; it matches the priority_rank column.
+func (p TaskPriority) Rank() int {
+	switch p {
+	case PriorityUrgent:
+		return 4
+	case PriorityHigh:
+		return 3
+	case PriorityMedium:
+		return 2
+	}
+	return 1
+}
+
+// DefaultTaskSort is used when no sort is requested: newest first.
+var DefaultTaskSort = []SortKey{{Field: "created_at", Desc: true}}
+
+// HasSortField reports whether the sort specification includes field.
+func HasSortField(sort []SortKey, field string) bool {
+	for _, key := range sort {
+		if key.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTaskSort parses a comma separated sort specification such as
+// "-priority,due_at,title". A leading "-" sorts the key descending; for
+// priority that means most urgent first.
+func ParseTaskSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !taskSortFields[key.Field] {
+			return nil, FieldValidationError("invalid_sort", "sort", "invalid sort field: "+part)
+		}
+		if seen[key.Field] {
+			return nil, FieldValidationError("invalid_sort", "sort", "duplicate sort field: "+key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	if len(keys) > maxSortKeys {
+		return nil, FieldValidationError("invalid_sort", "sort", "too many sort fields")
+	}
+	return keys, nil
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// errorCode returns the code of a domain error, or "" for nil.
+func errorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func TestParseTaskSort(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		want     []SortKey
+		wantCode string
+	}{
+		{"single key", "title", []SortKey{{Field: "title"}}, ""},
+		{"descending", "-priority", []SortKey{{Field: "priority", Desc: true}}, ""},
+		{"several keys", "-priority,due_at,title", []SortKey{{Field: "priority", Desc: true}, {Field: "due_at"}, {Field: "title"}}, ""},
+		{"spaces around keys", " due_at , -created_at ", []SortKey{{Field: "due_at"}, {Field: "created_at", Desc: true}}, ""},
+		{"unknown field", "owner", nil, "invalid_sort"},
+		{"empty spec", "", nil, "invalid_sort"},
+		{"empty key", "title,", nil, "invalid_sort"},
+		{"duplicate field", "title,-title", nil, "invalid_sort"},
+		{"too many keys", "created_at,updated_at,due_at,priority,title,status", nil, "invalid_sort"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTaskSort(tt.spec)
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("ParseTaskSort(%q) error = %q, want %q", tt.spec, code, tt.wantCode)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTaskSort(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTaskSort(t *testing.T) {
+	for _, spec := range []string{"title", "-priority,due_at,title", "-created_at"} {
+		sort, err := ParseTaskSort(spec)
+		if err != nil {
+			t.Fatalf("ParseTaskSort(%q): %v", spec, err)
+		}
+		if got := FormatTaskSort(sort); got != spec {
+			t.Errorf("FormatTaskSort(ParseTaskSort(%q)) = %q", spec, got)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// RefreshToken is a single-use token that can be exchanged for a new access
+// token. Tokens obtained from the same login share a FamilyID so that reuse
+// of a rotated token can revoke the whole session. OrgID is the organization
+// the session is working in. AccessExpiresAt is the expiry of the access
+// token issued alongside, AccessJTI.
+type RefreshToken struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"user_id"`
+	OrgID           *string    `json:"org_id,omitempty"`
+	FamilyID        string     `json:"family_id"`
+	TokenHash       string     `json:"-"`
+	AccessJTI       string     `json:"-"`
+	AccessExpiresAt time.Time  `json:"-"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy      *string    `json:"replaced_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// RevokedToken records an access token ID (jti) that must no longer be
+// accepted even though its signature and expiry are still valid.
+type RevokedToken struct {
+	JTI       string    `json:"jti"`
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
@@ -10,28 +10,101 @@ const (
 	RoleAdmin UserRole = "admin"
 )
 
+// Defaults of the profile settings of new users.
+const (
+	DefaultTimezone = "UTC"
+	DefaultLocale   = "en"
+)
+
 type User struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"`
-	Role      UserRole  `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                    string     `json:"id"`
+	Email                 string     `json:"email"`
+	EmailVerifiedAt       *time.Time `json:"email_verified_at"`
+	Password              string     `json:"-"`
+	Role                  UserRole   `json:"role"`
+	DisplayName           *string    `json:"display_name"`
+	Timezone              string     `json:"timezone"`
+	Locale                string     `json:"locale"`
+	AvatarURL             *string    `json:"avatar_url"`
+	DisabledAt            *time.Time `json:"disabled_at,omitempty"`
+	PasswordResetRequired bool       `json:"password_reset_required,omitempty"`
+	MFAEnabled            bool       `json:"mfa_enabled"`
+	DeletedAt             *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
 }
 
+func (r UserRole) IsValid() bool {
+	return r == RoleUser || r == RoleAdmin
+}
+
+// LoginRequest carries NewPassword only when an admin has required the user
+// to choose a new password; it replaces the old one on successful login.
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	NewPassword string `json:"new_password,omitempty"`
 }
 
 type RegisterRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
-	Role     string `json:"role,omitempty"`
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	OrgID        string    `json:"org_id,omitempty"`
+	User         User      `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// UserFilter selects users for the admin user list. Query matches part of
+// the email address.
+type UserFilter struct {
+	Query    string
+	Role     *UserRole
+	Disabled *bool
+	Limit    int
+	Offset   int
+}
+
+type UserPage struct {
+	Data       []User `json:"data"`
+	HasMore    bool   `json:"has_more"`
+	TotalCount int    `json:"total_count"`
+}
+
+// UserTaskCounts summarizes a user's tasks across all organizations. Open and
+// Overdue count the tasks assigned to the user that are not closed.
+type UserTaskCounts struct {
+	Created  int `json:"created"`
+	Assigned int `json:"assigned"`
+	Open     int `json:"open"`
+	Overdue  int `json:"overdue"`
+}
+
+type UserDetail struct {
+	User
+	TaskCounts UserTaskCounts `json:"task_counts"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role UserRole `json:"role"`
 }
 
+// TaskDisposition decides what happens to the tasks a deleted user created.
+type TaskDisposition string
+
+const (
+	TasksDelete   TaskDisposition = "delete"
+	TasksReassign TaskDisposition = "reassign"
+)
+
+func (d TaskDisposition) IsValid() bool {
+	return d == TasksDelete || d == TasksReassign
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// EmailVerification is the pending confirmation of a user's email address.
+type EmailVerification struct {
+	UserID    string
+	Email     string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+// VerificationPolicy decides what users cannot do before they have verified
+// their email address.
+type VerificationPolicy string
+
+const (
+	// VerifyBeforeLogin blocks logging in
+	VerifyBeforeLogin VerificationPolicy = "login"
+	// VerifyBeforeTasks allows logging in but blocks creating tasks
+	VerifyBeforeTasks VerificationPolicy = "tasks"
+	// VerifyOptional restricts nothing
+	VerifyOptional VerificationPolicy = "none"
+)
+
+func (p VerificationPolicy) IsValid() bool {
+	return p == VerifyBeforeLogin || p == VerifyBeforeTasks || p == VerifyOptional
+}
@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// StatusCategory groups workflow statuses so that filters and the worker know
+// whether a task is waiting, being worked on or done, whatever its status is
+// called.
+type StatusCategory string
+
+const (
+	CategoryOpen   StatusCategory = "open"
+	CategoryActive StatusCategory = "active"
+	CategoryClosed StatusCategory = "closed"
+)
+
+// AnyStatus matches every status in a workflow transition.
+const AnyStatus TaskStatus = "*"
+
+// DefaultWorkflowID identifies the built-in workflow used by tasks without a
+// workflow of their own.
+const DefaultWorkflowID = "default"
+
+var statusNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// transitionFields are the task fields a transition may require.
+var transitionFields = map[string]func(t *Task) bool{
+	"description": func(t *Task) bool { return t.Description != "" },
+	"due_at":      func(t *Task) bool { return t.DueAt != nil },
+	"remind_at":   func(t *Task) bool { return t.RemindAt != nil },
+}
+
+type WorkflowStatus struct {
+	Name     TaskStatus     `json:"name"`
+	Category StatusCategory `json:"category"`
+}
+
+// WorkflowTransition allows moving a task from one status to another. Either
+// side may be AnyStatus. RequiredFields must be set on the task for the
+// transition to be allowed.
+type WorkflowTransition struct {
+	From           TaskStatus `json:"from"`
+	To             TaskStatus `json:"to"`
+	RequiredFields []string   `json:"required_fields,omitempty"`
+}
+
+type Workflow struct {
+	ID            string               `json:"id"`
+	UserID        string               `json:"user_id,omitempty"`
+	Name          string               `json:"name"`
+	InitialStatus TaskStatus           `json:"initial_status"`
+	Statuses      []WorkflowStatus     `json:"statuses"`
+	Transitions   []WorkflowTransition `json:"transitions"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+type CreateWorkflowRequest struct {
+	Name          string               `json:"name"`
+	InitialStatus TaskStatus           `json:"initial_status"`
+	Statuses      []WorkflowStatus     `json:"statuses"`
+	Transitions   []WorkflowTransition `json:"transitions"`
+}
+
+// DefaultWorkflow is the pending/in_progress/completed workflow tasks had
+// before workflows were configurable. Any status change is allowed.
+var DefaultWorkflow = &Workflow{
+	ID:            DefaultWorkflowID,
+	Name:          "Default",
+	InitialStatus: StatusPending,
+	Statuses: []WorkflowStatus{
+		{Name: StatusPending, Category: CategoryOpen},
+		{Name: StatusInProgress, Category: CategoryActive},
+		{Name: StatusCompleted, Category: CategoryClosed},
+	},
+	Transitions: []WorkflowTransition{
+		{From: AnyStatus, To: AnyStatus},
+	},
+}
+
+func (c StatusCategory) IsValid() bool {
+	switch c {
+	case CategoryOpen, CategoryActive, CategoryClosed:
+		return true
+	}
+	return false
+}
+
+// Validate checks that the workflow is well formed: unique, well named
+// statuses with valid categories, an initial status that is not closed, at
+// least one closed status for completion, and transitions between known
+// statuses requiring only known fields.
+func (w *Workflow) Validate() error {
+	if w.Name == "" {
+		return FieldValidationError("name_required", "name", "name is required")
+	}
+	if len(w.Name) > 100 {
+		return FieldValidationError("name_too_long", "name", "name must be at most 100 characters")
+	}
+	if len(w.Statuses) == 0 {
+		return FieldValidationError("invalid_workflow", "statuses", "at least one status is required")
+	}
+
+	seen := make(map[TaskStatus]bool)
+	for _, status := range w.Statuses {
+		if !statusNameRegex.MatchString(string(status.Name)) {
+			return FieldValidationError("invalid_workflow", "statuses",
+				fmt.Sprintf("invalid status name %q: use lowercase letters, digits and underscores", status.Name))
+		}
+		if !status.Category.IsValid() {
+			return FieldValidationError("invalid_workflow", "statuses",
+				fmt.Sprintf("status %s: category must be open, active or closed", status.Name))
+		}
+		if seen[status.Name] {
+			return FieldValidationError("invalid_workflow", "statuses", "duplicate status "+string(status.Name))
+		}
+		seen[status.Name] = true
+	}
+
+	if w.DoneStatus() == "" {
+		return FieldValidationError("invalid_workflow", "statuses", "at least one status must be closed")
+	}
+	if category, ok := w.Category(w.InitialStatus); !ok || category == CategoryClosed {
+		return FieldValidationError("invalid_workflow", "initial_status", "initial_status must be an open or active status of the workflow")
+	}
+
+	for _, t := range w.Transitions {
+		if (t.From != AnyStatus && !seen[t.From]) || (t.To != AnyStatus && !seen[t.To]) {
+			return FieldValidationError("invalid_workflow", "transitions",
+				fmt.Sprintf("transition %s -> %s refers to an unknown status", t.From, t.To))
+		}
+		for _, field := range t.RequiredFields {
+			if transitionFields[field] == nil {
+				return FieldValidationError("invalid_workflow", "transitions", "unknown required field "+field)
+			}
+		}
+	}
+	return nil
+}
+
+// Category returns the category of a status, reporting false if the status
+// is not part of the workflow.
+func (w *Workflow) Category(status TaskStatus) (StatusCategory, bool) {
+	for _, s := range w.Statuses {
+		if s.Name == status {
+			return s.Category, true
+		}
+	}
+	return "", false
+}
+
+// DoneStatus is the status tasks are moved to when they are completed
+// automatically: the first closed status.
+func (w *Workflow) DoneStatus() TaskStatus {
+	for _, s := range w.Statuses {
+		if s.Category == CategoryClosed {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// Transition returns the first transition allowing from -> to, or nil.
+func (w *Workflow) Transition(from, to TaskStatus) *WorkflowTransition {
+	for i, t := range w.Transitions {
+		if (t.From == from || t.From == AnyStatus) && (t.To == to || t.To == AnyStatus) {
+			return &w.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// CheckRequiredFields returns a validation error listing the fields the
+// transition requires but the task does not have.
+func (t *WorkflowTransition) CheckRequiredFields(task *Task) error {
+	var missing []FieldError
+	for _, field := range t.RequiredFields {
+		if isSet := transitionFields[field]; isSet != nil && !isSet(task) {
+			missing = append(missing, FieldError{Field: field, Message: field + " is required for this status change"})
+		}
+	}
+	if len(missing) > 0 {
+		return NewValidationError("transition_fields_required", "status change requires more fields", missing...)
+	}
+	return nil
+}
+
+// InvalidTransitionError reports a status change the workflow does not allow.
+func InvalidTransitionError(from, to TaskStatus) *Error {
+	message := fmt.Sprintf("status cannot change from %s to %s", from, to)
+	return FieldValidationError("invalid_transition", "status", message)
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorkflowValidate(t *testing.T) {
+	valid := func() *Workflow {
+		return &Workflow{
+			Name:          "Review",
+			InitialStatus: "todo",
+			Statuses: []WorkflowStatus{
+				{Name: "todo", Category: CategoryOpen},
+				{Name: "in_review", Category: CategoryActive},
+				{Name: "done", Category: CategoryClosed},
+			},
+			Transitions: []WorkflowTransition{
+				{From: "todo", To: "in_review", RequiredFields: []string{"description"}},
+				{From: AnyStatus, To: "done"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		change    func(w *Workflow)
+		wantCode  string
+		wantField string
+	}{
+		{"valid", func(w *Workflow) {}, "", ""},
+		{"default workflow", func(w *Workflow) { *w = *DefaultWorkflow }, "", ""},
+		{"name required", func(w *Workflow) { w.Name = "" }, "name_required", "name"},
+		{"name too long", func(w *Workflow) { w.Name = strings.Repeat("x", 101) }, "name_too_long", "name"},
+		{"no statuses", func(w *Workflow) { w.Statuses = nil }, "invalid_workflow", "statuses"},
+		{"invalid status name", func(w *Workflow) { w.Statuses[0].Name = "To Do" }, "invalid_workflow", "statuses"},
+		{"invalid category", func(w *Workflow) { w.Statuses[1].Category = "waiting" }, "invalid_workflow", "statuses"},
+		{"duplicate status", func(w *Workflow) { w.Statuses[1].Name = "todo" }, "invalid_workflow", "statuses"},
+		{"no closed status", func(w *Workflow) { w.Statuses[2].Category = CategoryActive }, "invalid_workflow", "statuses"},
+		{"unknown initial status", func(w *Workflow) { w.InitialStatus = "backlog" }, "invalid_workflow", "initial_status"},
+		{"closed initial status", func(w *Workflow) { w.InitialStatus = "done" }, "invalid_workflow", "initial_status"},
+		{"transition from unknown status", func(w *Workflow) { w.Transitions[0].From = "backlog" }, "invalid_workflow", "transitions"},
+		{"transition to unknown status", func(w *Workflow) { w.Transitions[1].To = "archived" }, "invalid_workflow", "transitions"},
+		{"unknown required field", func(w *Workflow) { w.Transitions[0].RequiredFields = []string{"owner"} }, "invalid_workflow", "transitions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := valid()
+			tt.change(w)
+
+			err := w.Validate()
+			if code := errorCode(err); code != tt.wantCode {
+				t.Fatalf("Validate() error = %q, want %q", code, tt.wantCode)
+			}
+			if err == nil {
+				return
+			}
+			if fields := err.(*Error).Fields; len(fields) != 1 || fields[0].Field != tt.wantField {
+				t.Errorf("Validate() fields = %+v, want %s", fields, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestWorkflowTransition(t *testing.T) {
+	w := &Workflow{
+		Transitions: []WorkflowTransition{
+			{From: "todo", To: "in_review", RequiredFields: []string{"description"}},
+			{From: AnyStatus, To: "done"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		from, to TaskStatus
+		want     *WorkflowTransition
+	}{
+		{"exact match", "todo", "in_review", &w.Transitions[0]},
+		{"any source", "in_review", "done", &w.Transitions[1]},
+		{"not allowed", "in_review", "todo", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := w.Transition(tt.from, tt.to); got != tt.want {
+				t.Errorf("Transition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+
+	missing := w.Transitions[0].CheckRequiredFields(&Task{})
+	if code := errorCode(missing); code != "transition_fields_required" {
+		t.Errorf("CheckRequiredFields() without description = %q, want transition_fields_required", code)
+	}
+	if err := w.Transitions[0].CheckRequiredFields(&Task{Description: "details"}); err != nil {
+		t.Errorf("CheckRequiredFields() with description = %v, want nil", err)
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AccessTokenHandler serves the /me/tokens endpoints, through which users
+// manage their personal access tokens.
+type AccessTokenHandler struct {
+	accessTokenService service.AccessTokenService
+}
+
+func NewAccessTokenHandler(accessTokenService service.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{accessTokenService: accessTokenService}
+}
+
+func (h *AccessTokenHandler) Create(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*service.Claims)
+
+	var req domain.CreateAccessTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if err := util.ValidateAccessTokenName(req.Name); err != nil {
+		return util.SendError(c, err)
+	}
+
+	token, err := h.accessTokenService.Create(claims, req)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusCreated, token)
+}
+
+func (h *AccessTokenHandler) List(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	tokens, err := h.accessTokenService.List(userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, tokens)
+}
+
+func (h *AccessTokenHandler) Revoke(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	if err := h.accessTokenService.Revoke(userID, c.Params("id")); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminHandler struct {
+	adminService service.AdminService
+}
+
+func NewAdminHandler(adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+func (h *AdminHandler) ListAdmins(c *fiber.Ctx) error {
+	admins, err := h.adminService.ListAdmins()
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, admins)
+}
+
+func (h *AdminHandler) Promote(c *fiber.Ctx) error {
+	userID := c.Params("userId")
+
+	user, err := h.adminService.Promote(userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *AdminHandler) Demote(c *fiber.Ctx) error {
+	userID := c.Params("userId")
+
+	user, err := h.adminService.Demote(userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *AdminHandler) Invite(c *fiber.Ctx) error {
+	var req domain.InviteAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if err := util.ValidateEmail(req.Email); err != nil {
+		return util.SendError(c, err)
+	}
+
+	userID := c.Locals("userID").(string)
+
+	invitation, err := h.adminService.Invite(req, userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusCreated, invitation)
+}
+
+func (h *AdminHandler) Invitations(c *fiber.Ctx) error {
+	invitations, err := h.adminService.Invitations()
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, invitations)
+}
+
+func (h *AdminHandler) RevokeInvitation(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.adminService.RevokeInvitation(id); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// AcceptInvitation is open to every signed-in user; the token decides.
+func (h *AdminHandler) AcceptInvitation(c *fiber.Ctx) error {
+	var req domain.AcceptInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+	if req.Token == "" {
+		return util.SendError(c, domain.ErrInvalidInvitation)
+	}
+
+	userID := c.Locals("userID").(string)
+
+	user, err := h.adminService.AcceptInvitation(req, userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
@@ -19,20 +19,20 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	var req domain.RegisterRequest
 	if err := c.BodyParser(&req); err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, "invalid request body")
+		return util.SendError(c, domain.ErrInvalidRequestBody)
 	}
 
 	if err := util.ValidateEmail(req.Email); err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, err.Error())
+		return util.SendError(c, err)
 	}
 
 	if err := util.ValidatePassword(req.Password); err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, err.Error())
+		return util.SendError(c, err)
 	}
 
 	user, err := h.authService.Register(req)
 	if err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, err.Error())
+		return util.SendError(c, err)
 	}
 
 	return util.SendSuccess(c, fiber.StatusCreated, user)
@@ -41,21 +41,111 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	var req domain.LoginRequest
 	if err := c.BodyParser(&req); err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, "invalid request body")
+		return util.SendError(c, domain.ErrInvalidRequestBody)
 	}
 
 	if err := util.ValidateEmail(req.Email); err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, err.Error())
+		return util.SendError(c, err)
 	}
 
 	if err := util.ValidatePassword(req.Password); err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, err.Error())
+		return util.SendError(c, err)
 	}
 
-	response, err := h.authService.Login(req)
+	if req.NewPassword != "" {
+		if err := util.ValidatePassword(req.NewPassword); err != nil {
+			return util.SendError(c, err)
+		}
+	}
+
+	response, challenge, err := h.authService.Login(req, c.IP())
 	if err != nil {
-		return util.SendError(c, fiber.StatusUnauthorized, err.Error())
+		return util.SendError(c, err)
+	}
+	if challenge != nil {
+		return util.SendSuccess(c, fiber.StatusOK, challenge)
 	}
 
 	return util.SendSuccess(c, fiber.StatusOK, response)
 }
+
+func (h *AuthHandler) VerifyMFA(c *fiber.Ctx) error {
+	var req domain.VerifyMFARequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.ChallengeToken == "" {
+		return util.SendError(c, domain.FieldValidationError("challenge_token_required", "challenge_token", "challenge_token is required"))
+	}
+
+	if req.Code == "" && req.RecoveryCode == "" {
+		return util.SendError(c, domain.FieldValidationError("code_required", "code", "code or recovery_code is required"))
+	}
+
+	response, err := h.authService.VerifyMFA(req, c.IP())
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, response)
+}
+
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req domain.RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.RefreshToken == "" {
+		return util.SendError(c, domain.FieldValidationError("refresh_token_required", "refresh_token", "refresh_token is required"))
+	}
+
+	response, err := h.authService.Refresh(req)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, response)
+}
+
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*service.Claims)
+
+	if err := h.authService.Logout(claims); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// SwitchOrg starts a new session in another organization of the user.
+func (h *AuthHandler) SwitchOrg(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*service.Claims)
+
+	var req domain.SwitchOrgRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.OrgID == "" {
+		return util.SendError(c, domain.FieldValidationError("org_id_required", "org_id", "org_id is required"))
+	}
+
+	response, err := h.authService.SwitchOrg(claims, req.OrgID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, response)
+}
+
+func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	if err := h.authService.LogoutAll(userID); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"task-management-api/internal/policy"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DependencyHandler struct {
+	dependencyService service.DependencyService
+}
+
+func NewDependencyHandler(dependencyService service.DependencyService) *DependencyHandler {
+	return &DependencyHandler{dependencyService: dependencyService}
+}
+
+func (h *DependencyHandler) List(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	subject := c.Locals("subject").(policy.Subject)
+
+	blockers, err := h.dependencyService.List(taskID, subject)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, blockers)
+}
+
+func (h *DependencyHandler) Add(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	blockerID := c.Params("blockerId")
+	subject := c.Locals("subject").(policy.Subject)
+
+	if err := h.dependencyService.Add(taskID, blockerID, subject); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func (h *DependencyHandler) Remove(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	blockerID := c.Params("blockerId")
+	subject := c.Locals("subject").(policy.Subject)
+
+	if err := h.dependencyService.Remove(taskID, blockerID, subject); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type EmailVerificationHandler struct {
+	verificationService service.EmailVerificationService
+}
+
+func NewEmailVerificationHandler(verificationService service.EmailVerificationService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{verificationService: verificationService}
+}
+
+func (h *EmailVerificationHandler) Verify(c *fiber.Ctx) error {
+	var req domain.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.Token == "" {
+		return util.SendError(c, domain.FieldValidationError("token_required", "token", "token is required"))
+	}
+
+	user, err := h.verificationService.Verify(req)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+// Resend answers 202 whether or not the email belongs to an account.
+func (h *EmailVerificationHandler) Resend(c *fiber.Ctx) error {
+	var req domain.ResendVerificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if err := util.ValidateEmail(req.Email); err != nil {
+		return util.SendError(c, err)
+	}
+
+	if err := h.verificationService.Resend(req); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).Send(nil)
+}
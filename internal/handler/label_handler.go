@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LabelHandler struct {
+	labelService service.LabelService
+}
+
+func NewLabelHandler(labelService service.LabelService) *LabelHandler {
+	return &LabelHandler{labelService: labelService}
+}
+
+func (h *LabelHandler) Create(c *fiber.Ctx) error {
+	var req domain.CreateLabelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if err := util.ValidateLabelName(req.Name); err != nil {
+		return util.SendError(c, err)
+	}
+
+	if err := util.ValidateLabelColor(req.Color); err != nil {
+		return util.SendError(c, err)
+	}
+
+	userID := c.Locals("userID").(string)
+
+	label, err := h.labelService.Create(req, userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusCreated, label)
+}
+
+func (h *LabelHandler) List(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	labels, err := h.labelService.List(userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, labels)
+}
+
+func (h *LabelHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+	subject := c.Locals("subject").(policy.Subject)
+
+	var req domain.UpdateLabelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.Name != nil {
+		if err := util.ValidateLabelName(*req.Name); err != nil {
+			return util.SendError(c, err)
+		}
+	}
+
+	if req.Color != nil {
+		if err := util.ValidateLabelColor(*req.Color); err != nil {
+			return util.SendError(c, err)
+		}
+	}
+
+	label, err := h.labelService.Update(id, req, subject)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, label)
+}
+
+func (h *LabelHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	subject := c.Locals("subject").(policy.Subject)
+
+	if err := h.labelService.Delete(id, subject); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func (h *LabelHandler) Attach(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	labelID := c.Params("labelId")
+	subject := c.Locals("subject").(policy.Subject)
+
+	if err := h.labelService.AttachToTask(taskID, labelID, subject); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func (h *LabelHandler) Detach(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	labelID := c.Params("labelId")
+	subject := c.Locals("subject").(policy.Subject)
+
+	if err := h.labelService.DetachFromTask(taskID, labelID, subject); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MFAHandler serves the /me/mfa endpoints, through which users manage their
+// second factor.
+type MFAHandler struct {
+	mfaService service.MFAService
+}
+
+func NewMFAHandler(mfaService service.MFAService) *MFAHandler {
+	return &MFAHandler{mfaService: mfaService}
+}
+
+func (h *MFAHandler) Status(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	status, err := h.mfaService.Status(userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, status)
+}
+
+func (h *MFAHandler) Enroll(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req domain.EnrollMFARequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.Password == "" {
+		return util.SendError(c, domain.FieldValidationError("password_required", "password", "password is required"))
+	}
+
+	enrollment, err := h.mfaService.Enroll(userID, req)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusCreated, enrollment)
+}
+
+func (h *MFAHandler) Confirm(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req domain.ConfirmMFARequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.Code == "" {
+		return util.SendError(c, domain.FieldValidationError("code_required", "code", "code is required"))
+	}
+
+	codes, err := h.mfaService.Confirm(userID, req)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, codes)
+}
+
+func (h *MFAHandler) Disable(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req domain.DisableMFARequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.Password == "" {
+		return util.SendError(c, domain.FieldValidationError("password_required", "password", "password is required"))
+	}
+
+	if req.Code == "" {
+		return util.SendError(c, domain.FieldValidationError("code_required", "code", "code is required"))
+	}
+
+	if err := h.mfaService.Disable(userID, req); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req domain.RegenerateRecoveryCodesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.Password == "" {
+		return util.SendError(c, domain.FieldValidationError("password_required", "password", "password is required"))
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(userID, req)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, codes)
+}
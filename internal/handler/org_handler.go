@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrgHandler struct {
+	orgService service.OrgService
+}
+
+func NewOrgHandler(orgService service.OrgService) *OrgHandler {
+	return &OrgHandler{orgService: orgService}
+}
+
+func (h *OrgHandler) Create(c *fiber.Ctx) error {
+	var req domain.CreateOrgRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	userID := c.Locals("userID").(string)
+
+	org, err := h.orgService.Create(req, userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusCreated, org)
+}
+
+func (h *OrgHandler) List(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	orgs, err := h.orgService.List(userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, orgs)
+}
+
+func (h *OrgHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	org, err := h.orgService.GetByID(id, userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, org)
+}
+
+func (h *OrgHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	if err := h.orgService.Delete(id, userID); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+func (h *OrgHandler) Members(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	members, err := h.orgService.Members(id, userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, members)
+}
+
+func (h *OrgHandler) AddMember(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	var req domain.AddMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if err := util.ValidateEmail(req.Email); err != nil {
+		return util.SendError(c, err)
+	}
+
+	member, err := h.orgService.AddMember(id, req, userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusCreated, member)
+}
+
+func (h *OrgHandler) UpdateMember(c *fiber.Ctx) error {
+	id := c.Params("id")
+	memberID := c.Params("userId")
+	userID := c.Locals("userID").(string)
+
+	var req domain.UpdateMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	member, err := h.orgService.UpdateMember(id, memberID, req, userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, member)
+}
+
+func (h *OrgHandler) RemoveMember(c *fiber.Ctx) error {
+	id := c.Params("id")
+	memberID := c.Params("userId")
+	userID := c.Locals("userID").(string)
+
+	if err := h.orgService.RemoveMember(id, memberID, userID); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
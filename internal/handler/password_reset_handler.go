@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PasswordResetHandler struct {
+	passwordResetService service.PasswordResetService
+}
+
+func NewPasswordResetHandler(passwordResetService service.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{passwordResetService: passwordResetService}
+}
+
+// Forgot answers 202 whether or not the email belongs to an account.
+func (h *PasswordResetHandler) Forgot(c *fiber.Ctx) error {
+	var req domain.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if err := util.ValidateEmail(req.Email); err != nil {
+		return util.SendError(c, err)
+	}
+
+	if err := h.passwordResetService.Forgot(req); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).Send(nil)
+}
+
+func (h *PasswordResetHandler) Reset(c *fiber.Ctx) error {
+	var req domain.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.Token == "" {
+		return util.SendError(c, domain.FieldValidationError("token_required", "token", "token is required"))
+	}
+
+	if err := util.ValidatePassword(req.NewPassword); err != nil {
+		return util.SendError(c, err)
+	}
+
+	if err := h.passwordResetService.Reset(req); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"task-management-api/internal/policy"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PermissionHandler struct {
+	policy      *policy.Policy
+	taskService service.TaskService
+}
+
+func NewPermissionHandler(p *policy.Policy, taskService service.TaskService) *PermissionHandler {
+	return &PermissionHandler{policy: p, taskService: taskService}
+}
+
+// Get returns what the caller may do in their organization, or with the task
+// given by the task_id query parameter.
+func (h *PermissionHandler) Get(c *fiber.Ctx) error {
+	subject := c.Locals("subject").(policy.Subject)
+
+	perms := policy.Permissions{
+		Role:    subject.Role,
+		OrgID:   subject.OrgID,
+		OrgRole: subject.OrgRole,
+	}
+
+	if taskID := c.Query("task_id"); taskID != "" {
+		actions, err := h.taskService.Permissions(taskID, subject)
+		if err != nil {
+			return util.SendError(c, err)
+		}
+		perms.TaskID = taskID
+		perms.Actions = actions
+	} else {
+		perms.Actions = h.policy.Allowed(subject, policy.OrgResource(subject.OrgID), policy.OrgActions...)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, perms)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProfileHandler serves the /me endpoints, through which users manage their
+// own account.
+type ProfileHandler struct {
+	profileService service.ProfileService
+}
+
+func NewProfileHandler(profileService service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+func (h *ProfileHandler) Get(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	user, err := h.profileService.Get(userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *ProfileHandler) Update(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req domain.UpdateProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.DisplayName != nil {
+		if err := util.ValidateDisplayName(*req.DisplayName); err != nil {
+			return util.SendError(c, err)
+		}
+	}
+	if req.Timezone != nil {
+		if err := util.ValidateTimezone(*req.Timezone); err != nil {
+			return util.SendError(c, err)
+		}
+	}
+	if req.Locale != nil {
+		if err := util.ValidateLocale(*req.Locale); err != nil {
+			return util.SendError(c, err)
+		}
+	}
+	if req.AvatarURL != nil && *req.AvatarURL != "" {
+		if err := util.ValidateAvatarURL(*req.AvatarURL); err != nil {
+			return util.SendError(c, err)
+		}
+	}
+
+	user, err := h.profileService.Update(userID, req)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *ProfileHandler) ChangePassword(c *fiber.Ctx) error {
+	claims := c.Locals("claims").(*service.Claims)
+
+	var req domain.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.CurrentPassword == "" {
+		return util.SendError(c, domain.FieldValidationError("current_password_required", "current_password", "current_password is required"))
+	}
+
+	if err := util.ValidatePassword(req.NewPassword); err != nil {
+		return util.SendError(c, err)
+	}
+
+	if err := h.profileService.ChangePassword(claims, req); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// RequestEmailChange answers 202 as the change only applies once the new
+// address is confirmed.
+func (h *ProfileHandler) RequestEmailChange(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req domain.ChangeEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if err := util.ValidateEmail(req.Email); err != nil {
+		return util.SendError(c, err)
+	}
+
+	if req.Password == "" {
+		return util.SendError(c, domain.FieldValidationError("password_required", "password", "password is required"))
+	}
+
+	if err := h.profileService.RequestEmailChange(userID, req); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).Send(nil)
+}
+
+func (h *ProfileHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	var req domain.ConfirmEmailChangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.Token == "" {
+		return util.SendError(c, domain.FieldValidationError("token_required", "token", "token is required"))
+	}
+
+	user, err := h.profileService.ConfirmEmailChange(req)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *ProfileHandler) Delete(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req domain.DeleteAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	if req.Password == "" {
+		return util.SendError(c, domain.FieldValidationError("password_required", "password", "password is required"))
+	}
+
+	if err := h.profileService.DeleteAccount(userID, req); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
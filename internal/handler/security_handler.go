@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SecurityHandler serves the security audit trail to admins.
+type SecurityHandler struct {
+	loginGuard service.LoginGuard
+}
+
+func NewSecurityHandler(loginGuard service.LoginGuard) *SecurityHandler {
+	return &SecurityHandler{loginGuard: loginGuard}
+}
+
+func (h *SecurityHandler) Events(c *fiber.Ctx) error {
+	filter := domain.SecurityEventFilter{
+		Email: c.Query("email"),
+		IP:    c.Query("ip"),
+		Limit: domain.DefaultPageSize,
+	}
+
+	if eventType := c.Query("type"); eventType != "" {
+		t := domain.SecurityEventType(eventType)
+		if !t.IsValid() {
+			return util.SendError(c, domain.ErrInvalidSecurityEventType)
+		}
+		filter.Type = &t
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			return util.SendError(c, domain.FieldValidationError("invalid_user_id", "user_id", "user_id must be a UUID"))
+		}
+		filter.UserID = userID
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return util.SendError(c, domain.FieldValidationError("invalid_since", "since", "since must be an RFC 3339 timestamp"))
+		}
+		filter.Since = &t
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > domain.MaxPageSize {
+			return util.SendError(c, domain.FieldValidationError("invalid_limit", "limit",
+				fmt.Sprintf("limit must be between 1 and %d", domain.MaxPageSize)))
+		}
+		filter.Limit = l
+	}
+
+	if offset := c.Query("offset"); offset != "" {
+		o, err := strconv.Atoi(offset)
+		if err != nil || o < 0 {
+			return util.SendError(c, domain.FieldValidationError("invalid_offset", "offset", "offset must be a non-negative integer"))
+		}
+		filter.Offset = o
+	}
+
+	page, err := h.loginGuard.Events(filter)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(page)
+}
@@ -1,13 +1,17 @@
 package handler
 
 import (
+	"fmt"
+	"log"
 	"strconv"
 
 	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
 	"task-management-api/internal/service"
 	"task-management-api/internal/util"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type TaskHandler struct {
@@ -25,80 +29,174 @@ func NewTaskHandler(taskService service.TaskService, workerService service.Worke
 func (h *TaskHandler) Create(c *fiber.Ctx) error {
 	var req domain.CreateTaskRequest
 	if err := c.BodyParser(&req); err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, "invalid request body")
+		return util.SendError(c, domain.ErrInvalidRequestBody)
 	}
 
 	if err := util.ValidateTaskTitle(req.Title); err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, err.Error())
+		return util.SendError(c, err)
 	}
 
-	userID := c.Locals("userID").(string)
+	subject := c.Locals("subject").(policy.Subject)
 
-	task, err := h.taskService.Create(req, userID)
+	task, err := h.taskService.Create(req, subject)
 	if err != nil {
-		return util.SendError(c, fiber.StatusInternalServerError, err.Error())
+		return util.SendError(c, err)
 	}
 
-	// Enqueue task for auto-completion
-	h.workerService.EnqueueTask(task.ID)
+	// Enqueue task for auto-completion. A failure here is picked up later by
+	// the worker's periodic scan, so the request still succeeds.
+	if err := h.workerService.EnqueueTask(task); err != nil {
+		log.Printf("Failed to enqueue task %s: %v", task.ID, err)
+	}
+	if err := h.workerService.ScheduleDueDates(task); err != nil {
+		log.Printf("Failed to schedule due dates of task %s: %v", task.ID, err)
+	}
 
 	return util.SendSuccess(c, fiber.StatusCreated, task)
 }
 
 func (h *TaskHandler) List(c *fiber.Ctx) error {
-	userID := c.Locals("userID").(string)
-	userRole := c.Locals("userRole").(string)
-	isAdmin := userRole == string(domain.RoleAdmin)
+	subject := c.Locals("subject").(policy.Subject)
 
 	filter := domain.TaskFilter{
-		Limit:  50,
-		Offset: 0,
+		Limit: domain.DefaultPageSize,
 	}
 
 	// Parse query parameters
+	// Statuses depend on each task's workflow, so any name is accepted
 	if status := c.Query("status"); status != "" {
 		taskStatus := domain.TaskStatus(status)
-		if !taskStatus.IsValid() {
-			return util.SendError(c, fiber.StatusBadRequest, "invalid status parameter")
-		}
 		filter.Status = &taskStatus
 	}
 
+	if category := c.Query("status_category"); category != "" {
+		statusCategory := domain.StatusCategory(category)
+		if !statusCategory.IsValid() {
+			return util.SendError(c, domain.FieldValidationError("invalid_status_category", "status_category", "status_category must be open, active or closed"))
+		}
+		filter.Category = &statusCategory
+	}
+
+	if due := c.Query("due"); due != "" {
+		window := domain.DueWindow(due)
+		if !window.IsValid() {
+			return util.SendError(c, domain.FieldValidationError("invalid_due", "due", "invalid due parameter"))
+		}
+		filter.Due = &window
+	}
+
+	if blocked := c.Query("blocked"); blocked != "" {
+		value, err := strconv.ParseBool(blocked)
+		if err != nil {
+			return util.SendError(c, domain.FieldValidationError("invalid_blocked", "blocked", "blocked must be true or false"))
+		}
+		filter.Blocked = &value
+	}
+
+	// "me" stands for the current user, e.g. ?assignee=me
+	if assignee := c.Query("assignee"); assignee != "" {
+		id, err := parseUserParam(assignee, subject.UserID)
+		if err != nil {
+			return util.SendError(c, domain.FieldValidationError("invalid_assignee", "assignee", "assignee must be me or a user id"))
+		}
+		filter.AssigneeID = id
+	}
+
+	if createdBy := c.Query("created_by"); createdBy != "" {
+		id, err := parseUserParam(createdBy, subject.UserID)
+		if err != nil {
+			return util.SendError(c, domain.FieldValidationError("invalid_created_by", "created_by", "created_by must be me or a user id"))
+		}
+		filter.CreatedBy = id
+	}
+
+	// Repeated label parameters, e.g. ?label=bug&label=frontend
+	seen := make(map[string]bool)
+	for _, label := range c.Context().QueryArgs().PeekMulti("label") {
+		if name := string(label); name != "" && !seen[name] {
+			seen[name] = true
+			filter.Labels = append(filter.Labels, name)
+		}
+	}
+
+	filter.LabelMatch = domain.LabelMatchAny
+	if match := c.Query("label_match"); match != "" {
+		filter.LabelMatch = domain.LabelMatch(match)
+		if !filter.LabelMatch.IsValid() {
+			return util.SendError(c, domain.FieldValidationError("invalid_label_match", "label_match", "label_match must be any or all"))
+		}
+	}
+
+	if q := c.Query("q"); q != "" {
+		search, err := domain.ParseSearchQuery(q)
+		if err != nil {
+			return util.SendError(c, err)
+		}
+		filter.Search = search
+	}
+
+	// Search results are ordered by relevance unless a sort is requested
+	filter.Sort = domain.DefaultTaskSort
+	if filter.Search != "" {
+		filter.Sort = domain.DefaultSearchSort
+	}
+	if sort := c.Query("sort"); sort != "" {
+		keys, err := domain.ParseTaskSort(sort)
+		if err != nil {
+			return util.SendError(c, err)
+		}
+		filter.Sort = keys
+	}
+	if filter.Search == "" && domain.HasSortField(filter.Sort, "rank") {
+		return util.SendError(c, domain.FieldValidationError("invalid_sort", "sort", "rank sort requires a search query"))
+	}
+
 	if limit := c.Query("limit"); limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
-			filter.Limit = l
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > domain.MaxPageSize {
+			return util.SendError(c, domain.FieldValidationError("invalid_limit", "limit",
+				fmt.Sprintf("limit must be between 1 and %d", domain.MaxPageSize)))
 		}
+		filter.Limit = l
 	}
 
 	if offset := c.Query("offset"); offset != "" {
-		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
-			filter.Offset = o
+		o, err := strconv.Atoi(offset)
+		if err != nil || o < 0 {
+			return util.SendError(c, domain.FieldValidationError("invalid_offset", "offset", "offset must be a non-negative integer"))
+		}
+		filter.Offset = o
+	}
+
+	// Cursors are tied to the sort order they were issued for
+	if token := c.Query("cursor"); token != "" {
+		if filter.Offset > 0 {
+			return util.SendError(c, domain.NewValidationError("cursor_with_offset", "cursor and offset cannot be combined"))
 		}
+		cursor, err := domain.DecodeTaskCursor(token, filter.Sort)
+		if err != nil {
+			return util.SendError(c, err)
+		}
+		filter.Cursor = cursor
 	}
 
-	tasks, err := h.taskService.List(filter, userID, isAdmin)
+	filter.WithTotal = c.QueryBool("count", false)
+
+	page, err := h.taskService.List(filter, subject)
 	if err != nil {
-		return util.SendError(c, fiber.StatusInternalServerError, err.Error())
+		return util.SendError(c, err)
 	}
 
-	return util.SendSuccess(c, fiber.StatusOK, tasks)
+	return c.Status(fiber.StatusOK).JSON(page)
 }
 
 func (h *TaskHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	userID := c.Locals("userID").(string)
-	userRole := c.Locals("userRole").(string)
-	isAdmin := userRole == string(domain.RoleAdmin)
+	subject := c.Locals("subject").(policy.Subject)
 
-	task, err := h.taskService.GetByID(id, userID, isAdmin)
+	task, err := h.taskService.GetByID(id, subject)
 	if err != nil {
-		status := fiber.StatusInternalServerError
-		if err.Error() == "task not found" {
-			status = fiber.StatusNotFound
-		} else if err.Error() == "unauthorized access" {
-			status = fiber.StatusForbidden
-		}
-		return util.SendError(c, status, err.Error())
+		return util.SendError(c, err)
 	}
 
 	return util.SendSuccess(c, fiber.StatusOK, task)
@@ -106,32 +204,36 @@ func (h *TaskHandler) GetByID(c *fiber.Ctx) error {
 
 func (h *TaskHandler) Update(c *fiber.Ctx) error {
 	id := c.Params("id")
-	userID := c.Locals("userID").(string)
-	userRole := c.Locals("userRole").(string)
-	isAdmin := userRole == string(domain.RoleAdmin)
+	subject := c.Locals("subject").(policy.Subject)
 
 	var req domain.UpdateTaskRequest
 	if err := c.BodyParser(&req); err != nil {
-		return util.SendError(c, fiber.StatusBadRequest, "invalid request body")
+		return util.SendError(c, domain.ErrInvalidRequestBody)
 	}
 
 	if req.Title != nil {
 		if err := util.ValidateTaskTitle(*req.Title); err != nil {
-			return util.SendError(c, fiber.StatusBadRequest, err.Error())
+			return util.SendError(c, err)
 		}
 	}
 
-	task, err := h.taskService.Update(id, req, userID, isAdmin)
+	task, err := h.taskService.Update(id, req, subject)
 	if err != nil {
-		status := fiber.StatusInternalServerError
-		if err.Error() == "task not found" {
-			status = fiber.StatusNotFound
-		} else if err.Error() == "unauthorized access" {
-			status = fiber.StatusForbidden
-		} else if err.Error() == "invalid status" {
-			status = fiber.StatusBadRequest
+		return util.SendError(c, err)
+	}
+
+	// Reschedule auto-completion when the policy changed
+	if req.AutoComplete != nil {
+		if err := h.workerService.EnqueueTask(task); err != nil {
+			log.Printf("Failed to reschedule task %s: %v", task.ID, err)
+		}
+	}
+
+	// Reschedule reminders when the dates changed or the task was completed
+	if req.DueAt != nil || req.RemindAt != nil || req.Status != nil {
+		if err := h.workerService.ScheduleDueDates(task); err != nil {
+			log.Printf("Failed to schedule due dates of task %s: %v", task.ID, err)
 		}
-		return util.SendError(c, status, err.Error())
 	}
 
 	return util.SendSuccess(c, fiber.StatusOK, task)
@@ -139,20 +241,77 @@ func (h *TaskHandler) Update(c *fiber.Ctx) error {
 
 func (h *TaskHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
-	userID := c.Locals("userID").(string)
-	userRole := c.Locals("userRole").(string)
-	isAdmin := userRole == string(domain.RoleAdmin)
+	subject := c.Locals("subject").(policy.Subject)
 
-	err := h.taskService.Delete(id, userID, isAdmin)
+	err := h.taskService.Delete(id, subject)
 	if err != nil {
-		status := fiber.StatusInternalServerError
-		if err.Error() == "task not found" {
-			status = fiber.StatusNotFound
-		} else if err.Error() == "unauthorized access" {
-			status = fiber.StatusForbidden
-		}
-		return util.SendError(c, status, err.Error())
+		return util.SendError(c, err)
 	}
 
 	return c.Status(fiber.StatusNoContent).Send(nil)
 }
+
+func (h *TaskHandler) Children(c *fiber.Ctx) error {
+	id := c.Params("id")
+	subject := c.Locals("subject").(policy.Subject)
+
+	children, err := h.taskService.Children(id, subject)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, children)
+}
+
+func (h *TaskHandler) Tree(c *fiber.Ctx) error {
+	id := c.Params("id")
+	subject := c.Locals("subject").(policy.Subject)
+
+	tree, err := h.taskService.Tree(id, subject)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, tree)
+}
+
+func (h *TaskHandler) Reassign(c *fiber.Ctx) error {
+	id := c.Params("id")
+	subject := c.Locals("subject").(policy.Subject)
+
+	var req domain.ReassignRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	task, err := h.taskService.Reassign(id, req, subject)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, task)
+}
+
+func (h *TaskHandler) AssignmentHistory(c *fiber.Ctx) error {
+	id := c.Params("id")
+	subject := c.Locals("subject").(policy.Subject)
+
+	history, err := h.taskService.AssignmentHistory(id, subject)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, history)
+}
+
+// parseUserParam resolves a user query parameter, which is either "me" or a
+// user ID.
+func parseUserParam(value, userID string) (string, error) {
+	if value == "me" {
+		return userID, nil
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
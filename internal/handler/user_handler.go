@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserHandler serves the admin user-management endpoints.
+type UserHandler struct {
+	userService service.UserService
+}
+
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+func (h *UserHandler) List(c *fiber.Ctx) error {
+	filter := domain.UserFilter{
+		Query: c.Query("q"),
+		Limit: domain.DefaultPageSize,
+	}
+
+	if role := c.Query("role"); role != "" {
+		r := domain.UserRole(role)
+		if !r.IsValid() {
+			return util.SendError(c, domain.ErrInvalidUserRole)
+		}
+		filter.Role = &r
+	}
+
+	switch c.Query("status") {
+	case "":
+	case "active":
+		disabled := false
+		filter.Disabled = &disabled
+	case "disabled":
+		disabled := true
+		filter.Disabled = &disabled
+	default:
+		return util.SendError(c, domain.ErrInvalidUserStatusFilter)
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > domain.MaxPageSize {
+			return util.SendError(c, domain.FieldValidationError("invalid_limit", "limit",
+				fmt.Sprintf("limit must be between 1 and %d", domain.MaxPageSize)))
+		}
+		filter.Limit = l
+	}
+
+	if offset := c.Query("offset"); offset != "" {
+		o, err := strconv.Atoi(offset)
+		if err != nil || o < 0 {
+			return util.SendError(c, domain.FieldValidationError("invalid_offset", "offset", "offset must be a non-negative integer"))
+		}
+		filter.Offset = o
+	}
+
+	page, err := h.userService.List(filter)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+func (h *UserHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	user, err := h.userService.GetByID(id)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *UserHandler) UpdateRole(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req domain.UpdateUserRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	user, err := h.userService.UpdateRole(id, req)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *UserHandler) Disable(c *fiber.Ctx) error {
+	id := c.Params("id")
+	adminID := c.Locals("userID").(string)
+
+	user, err := h.userService.Disable(id, adminID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *UserHandler) Enable(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	user, err := h.userService.Enable(id)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *UserHandler) RequirePasswordReset(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	user, err := h.userService.RequirePasswordReset(id)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+func (h *UserHandler) ResetMFA(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	user, err := h.userService.ResetMFA(id)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, user)
+}
+
+// Delete takes the fate of the user's tasks from the tasks query parameter,
+// delete or reassign; reassign also needs reassign_to.
+func (h *UserHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	adminID := c.Locals("userID").(string)
+	disposition := domain.TaskDisposition(c.Query("tasks"))
+
+	if err := h.userService.Delete(id, disposition, c.Query("reassign_to"), adminID); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
+	"task-management-api/internal/service"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WorkflowHandler struct {
+	workflowService service.WorkflowService
+}
+
+func NewWorkflowHandler(workflowService service.WorkflowService) *WorkflowHandler {
+	return &WorkflowHandler{workflowService: workflowService}
+}
+
+func (h *WorkflowHandler) Create(c *fiber.Ctx) error {
+	var req domain.CreateWorkflowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.SendError(c, domain.ErrInvalidRequestBody)
+	}
+
+	userID := c.Locals("userID").(string)
+
+	workflow, err := h.workflowService.Create(req, userID)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusCreated, workflow)
+}
+
+func (h *WorkflowHandler) List(c *fiber.Ctx) error {
+	subject := c.Locals("subject").(policy.Subject)
+
+	workflows, err := h.workflowService.List(subject)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, workflows)
+}
+
+func (h *WorkflowHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	subject := c.Locals("subject").(policy.Subject)
+
+	workflow, err := h.workflowService.GetByID(id, subject)
+	if err != nil {
+		return util.SendError(c, err)
+	}
+
+	return util.SendSuccess(c, fiber.StatusOK, workflow)
+}
+
+func (h *WorkflowHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	subject := c.Locals("subject").(policy.Subject)
+
+	if err := h.workflowService.Delete(id, subject); err != nil {
+		return util.SendError(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
 	"task-management-api/internal/service"
 	"task-management-api/internal/util"
 
@@ -14,34 +15,97 @@ func AuthMiddleware(authService service.AuthService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			return util.SendError(c, fiber.StatusUnauthorized, "missing authorization header")
+			return util.SendError(c, domain.NewUnauthorizedError("missing_authorization", "missing authorization header"))
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			return util.SendError(c, fiber.StatusUnauthorized, "invalid authorization header format")
+			return util.SendError(c, domain.NewUnauthorizedError("invalid_authorization", "invalid authorization header format"))
 		}
 
 		token := parts[1]
 		claims, err := authService.ValidateToken(token)
 		if err != nil {
-			return util.SendError(c, fiber.StatusUnauthorized, "invalid or expired token")
+			return util.SendError(c, err)
+		}
+
+		// Personal access tokens only get into routes that name the scope
+		// they need
+		if claims.AccessToken != nil {
+			scope, ok := c.Locals("tokenScope").(domain.TokenScope)
+			if !ok {
+				return util.SendError(c, domain.ErrAccessTokenNotAllowed)
+			}
+			if !claims.AccessToken.HasScope(scope) {
+				return util.SendError(c, domain.ErrInsufficientScope)
+			}
 		}
 
 		// Store user info in context
 		c.Locals("userID", claims.UserID)
 		c.Locals("userEmail", claims.Email)
 		c.Locals("userRole", claims.Role)
+		c.Locals("claims", claims)
+		c.Locals("subject", policy.Subject{
+			UserID:  claims.UserID,
+			Role:    domain.UserRole(claims.Role),
+			OrgID:   claims.OrgID,
+			OrgRole: claims.OrgRole,
+		})
+
+		return c.Next()
+	}
+}
 
+// TokenScopeMiddleware lets personal access tokens into the routes that
+// follow: reads need the read scope and everything else the write scope. It
+// goes before AuthMiddleware; routes without it accept sessions only.
+func TokenScopeMiddleware(read, write domain.TokenScope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead:
+			c.Locals("tokenScope", read)
+		default:
+			c.Locals("tokenScope", write)
+		}
+		return c.Next()
+	}
+}
+
+// OrgMiddleware requires the session to work in an organization. What the
+// user may do there is decided by the policy.
+func OrgMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		subject, ok := c.Locals("subject").(policy.Subject)
+		if !ok || subject.OrgID == "" {
+			return util.SendError(c, domain.ErrNoOrganization)
+		}
+		return c.Next()
+	}
+}
+
+// VerifiedEmailMiddleware requires a verified email address when the
+// verification policy keeps unverified users from creating tasks.
+func VerifiedEmailMiddleware(verification domain.VerificationPolicy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if verification != domain.VerifyBeforeTasks {
+			return c.Next()
+		}
+		claims, ok := c.Locals("claims").(*service.Claims)
+		if !ok || !claims.EmailVerified {
+			return util.SendError(c, domain.ErrEmailNotVerified)
+		}
 		return c.Next()
 	}
 }
 
+// AdminMiddleware requires the platform operator role. It does not grant
+// access to the data of organizations.
 func AdminMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		role, ok := c.Locals("userRole").(string)
 		if !ok || role != string(domain.RoleAdmin) {
-			return util.SendError(c, fiber.StatusForbidden, "admin access required")
+			return util.SendError(c, domain.ErrAdminRequired)
 		}
 		return c.Next()
 	}
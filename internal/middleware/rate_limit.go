@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"time"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// RateLimit allows each client IP max requests per window. The counts are
+// kept in memory, so every server instance limits on its own.
+func RateLimit(max int, window time.Duration) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: window,
+		LimitReached: func(c *fiber.Ctx) error {
+			return util.SendError(c, domain.ErrTooManyRequests)
+		},
+	})
+}
@@ -0,0 +1,208 @@
+// Package policy decides what a subject (a user acting in an organization, or
+// the system itself) may do with a resource. Services ask it instead of
+// comparing user IDs and roles themselves, so the rules live in one table.
+package policy
+
+import "task-management-api/internal/domain"
+
+// Action names an operation on a kind of resource, e.g. "task:delete".
+type Action string
+
+const (
+	TaskCreate       Action = "task:create"
+	TaskReadAll      Action = "task:read:all"
+	TaskRead         Action = "task:read"
+	TaskUpdate       Action = "task:update"
+	TaskUpdateStatus Action = "task:update:status"
+	TaskAssign       Action = "task:assign"
+	TaskDelete       Action = "task:delete"
+
+	OrgRead          Action = "org:read"
+	OrgManageMembers Action = "org:members:manage"
+	OrgManageOwners  Action = "org:owners:manage"
+	OrgDelete        Action = "org:delete"
+
+	LabelUpdate    Action = "label:update"
+	LabelDelete    Action = "label:delete"
+	WorkflowRead   Action = "workflow:read"
+	WorkflowDelete Action = "workflow:delete"
+)
+
+// OrgActions are the actions that do not concern a single task, evaluated
+// against the subject's organization.
+var OrgActions = []Action{TaskCreate, TaskReadAll, OrgRead, OrgManageMembers, OrgManageOwners, OrgDelete}
+
+// TaskActions are the actions evaluated against a single task.
+var TaskActions = []Action{TaskRead, TaskUpdate, TaskUpdateStatus, TaskAssign, TaskDelete}
+
+// Subject is who is acting: a user with their platform role and their role
+// in the organization they are working in, or the system.
+type Subject struct {
+	UserID  string
+	Role    domain.UserRole
+	OrgID   string
+	OrgRole domain.OrgRole
+	System  bool
+}
+
+// System is the subject of the background worker.
+var System = Subject{System: true}
+
+// Resource is what is acted on. OwnerID is the creator of a task or the owner
+// of a personal resource such as a label.
+type Resource struct {
+	OrgID       string
+	OwnerID     string
+	AssigneeIDs []string
+}
+
+func TaskResource(task *domain.Task) Resource {
+	assignees := make([]string, len(task.Assignees))
+	for i, a := range task.Assignees {
+		assignees[i] = a.UserID
+	}
+	return Resource{OrgID: task.OrgID, OwnerID: task.UserID, AssigneeIDs: assignees}
+}
+
+func OrgResource(orgID string) Resource {
+	return Resource{OrgID: orgID}
+}
+
+// OwnedResource is a personal resource outside of organizations.
+func OwnedResource(ownerID string) Resource {
+	return Resource{OwnerID: ownerID}
+}
+
+// Permissions tells a client what the subject may do, either in its
+// organization or, when TaskID is set, with one task.
+type Permissions struct {
+	Role    domain.UserRole `json:"role"`
+	OrgID   string          `json:"org_id,omitempty"`
+	OrgRole domain.OrgRole  `json:"org_role,omitempty"`
+	TaskID  string          `json:"task_id,omitempty"`
+	Actions []Action        `json:"actions"`
+}
+
+// rule decides one action.
+type rule func(s Subject, r Resource) bool
+
+type Policy struct {
+	rules map[Action]rule
+}
+
+// New returns the policy of the application:
+//
+//   - within an organization, every role may read all of its tasks; owners
+//     and admins may do anything with them; members create tasks and manage
+//     the ones they created; assignees may change the status of a task;
+//     viewers change nothing
+//   - admins manage members, owners also manage owners and delete the
+//     organization
+//   - labels and workflows are managed by their owner and platform admins;
+//     workflows may also be read and used in every organization their owner
+//     belongs to
+//   - the system may read tasks and change their status
+func New() *Policy {
+	manager := atLeast(domain.OrgRoleAdmin)
+	writer := atLeast(domain.OrgRoleMember)
+	reader := atLeast(domain.OrgRoleViewer)
+
+	return &Policy{rules: map[Action]rule{
+		TaskCreate:       inOrg(writer),
+		TaskReadAll:      inOrg(reader),
+		TaskRead:         orSystem(inOrg(reader)),
+		TaskUpdate:       inOrg(allOf(writer, anyOf(manager, isOwner))),
+		TaskUpdateStatus: orSystem(inOrg(allOf(writer, anyOf(manager, isOwner, isAssignee)))),
+		TaskAssign:       inOrg(allOf(writer, anyOf(manager, isOwner))),
+		TaskDelete:       inOrg(allOf(writer, anyOf(manager, isOwner))),
+
+		OrgRead:          inOrg(reader),
+		OrgManageMembers: inOrg(manager),
+		OrgManageOwners:  inOrg(atLeast(domain.OrgRoleOwner)),
+		OrgDelete:        inOrg(atLeast(domain.OrgRoleOwner)),
+
+		LabelUpdate:    anyOf(isOwner, isPlatformAdmin),
+		LabelDelete:    anyOf(isOwner, isPlatformAdmin),
+		WorkflowRead:   anyOf(isOwner, isPlatformAdmin, inOrg(reader)),
+		WorkflowDelete: anyOf(isOwner, isPlatformAdmin),
+	}}
+}
+
+// Can reports whether the subject may perform the action on the resource.
+// Unknown actions are denied.
+func (p *Policy) Can(s Subject, action Action, r Resource) bool {
+	rule, ok := p.rules[action]
+	return ok && rule(s, r)
+}
+
+// Allowed returns the given actions the subject may perform on the resource.
+func (p *Policy) Allowed(s Subject, r Resource, actions ...Action) []Action {
+	allowed := []Action{}
+	for _, action := range actions {
+		if p.Can(s, action, r) {
+			allowed = append(allowed, action)
+		}
+	}
+	return allowed
+}
+
+// inOrg limits a rule to users acting in the resource's organization.
+func inOrg(next rule) rule {
+	return func(s Subject, r Resource) bool {
+		return !s.System && s.OrgID != "" && s.OrgID == r.OrgID && next(s, r)
+	}
+}
+
+func orSystem(next rule) rule {
+	return func(s Subject, r Resource) bool {
+		return s.System || next(s, r)
+	}
+}
+
+func atLeast(role domain.OrgRole) rule {
+	return func(s Subject, r Resource) bool {
+		return s.OrgRole.AtLeast(role)
+	}
+}
+
+func isOwner(s Subject, r Resource) bool {
+	return !s.System && s.UserID != "" && s.UserID == r.OwnerID
+}
+
+func isAssignee(s Subject, r Resource) bool {
+	if s.System || s.UserID == "" {
+		return false
+	}
+	for _, id := range r.AssigneeIDs {
+		if id == s.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+func isPlatformAdmin(s Subject, r Resource) bool {
+	return !s.System && s.Role == domain.RoleAdmin
+}
+
+func anyOf(rules ...rule) rule {
+	return func(s Subject, r Resource) bool {
+		for _, rule := range rules {
+			if rule(s, r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func allOf(rules ...rule) rule {
+	return func(s Subject, r Resource) bool {
+		for _, rule := range rules {
+			if !rule(s, r) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,232 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"task-management-api/internal/domain"
+)
+
+const (
+	orgA    = "org-a"
+	orgB    = "org-b"
+	creator = "user-creator"
+	other   = "user-other"
+)
+
+func member(userID string, role domain.OrgRole) Subject {
+	return Subject{UserID: userID, Role: domain.RoleUser, OrgID: orgA, OrgRole: role}
+}
+
+func task(assignees ...string) Resource {
+	return Resource{OrgID: orgA, OwnerID: creator, AssigneeIDs: assignees}
+}
+
+func TestCan(t *testing.T) {
+	p := New()
+
+	tests := []struct {
+		name    string
+		subject Subject
+		action  Action
+		res     Resource
+		want    bool
+	}{
+		// Creating and listing tasks of the organization
+		{"viewer cannot create", member(other, domain.OrgRoleViewer), TaskCreate, OrgResource(orgA), false},
+		{"member creates", member(other, domain.OrgRoleMember), TaskCreate, OrgResource(orgA), true},
+		{"member creates only in own org", member(other, domain.OrgRoleMember), TaskCreate, OrgResource(orgB), false},
+		{"member reads all", member(other, domain.OrgRoleMember), TaskReadAll, OrgResource(orgA), true},
+		{"admin reads all", member(other, domain.OrgRoleAdmin), TaskReadAll, OrgResource(orgA), true},
+		{"viewer reads all", member(other, domain.OrgRoleViewer), TaskReadAll, OrgResource(orgA), true},
+		{"viewer reads all only in own org", member(other, domain.OrgRoleViewer), TaskReadAll, OrgResource(orgB), false},
+		{"owner reads all", member(other, domain.OrgRoleOwner), TaskReadAll, OrgResource(orgA), true},
+
+		// Reading a task
+		{"creator reads", member(creator, domain.OrgRoleMember), TaskRead, task(), true},
+		{"assignee reads", member(other, domain.OrgRoleMember), TaskRead, task(other), true},
+		{"viewer assignee reads", member(other, domain.OrgRoleViewer), TaskRead, task(other), true},
+		{"viewer reads any task", member(other, domain.OrgRoleViewer), TaskRead, task(), true},
+		{"unrelated member reads", member(other, domain.OrgRoleMember), TaskRead, task(), true},
+		{"admin reads", member(other, domain.OrgRoleAdmin), TaskRead, task(), true},
+
+		// Updating a task
+		{"creator updates", member(creator, domain.OrgRoleMember), TaskUpdate, task(), true},
+		{"assignee cannot update", member(other, domain.OrgRoleMember), TaskUpdate, task(other), false},
+		{"admin updates", member(other, domain.OrgRoleAdmin), TaskUpdate, task(), true},
+		{"creator demoted to viewer cannot update", member(creator, domain.OrgRoleViewer), TaskUpdate, task(), false},
+		{"viewer cannot update", member(other, domain.OrgRoleViewer), TaskUpdate, task(), false},
+
+		// Changing the status of a task
+		{"assignee changes status", member(other, domain.OrgRoleMember), TaskUpdateStatus, task(other), true},
+		{"viewer assignee cannot change status", member(other, domain.OrgRoleViewer), TaskUpdateStatus, task(other), false},
+		{"unrelated member cannot change status", member(other, domain.OrgRoleMember), TaskUpdateStatus, task(), false},
+		{"creator changes status", member(creator, domain.OrgRoleMember), TaskUpdateStatus, task(), true},
+
+		// Assigning and deleting a task
+		{"creator assigns", member(creator, domain.OrgRoleMember), TaskAssign, task(), true},
+		{"assignee cannot assign", member(other, domain.OrgRoleMember), TaskAssign, task(other), false},
+		{"creator deletes", member(creator, domain.OrgRoleMember), TaskDelete, task(), true},
+		{"assignee cannot delete", member(other, domain.OrgRoleMember), TaskDelete, task(other), false},
+		{"admin deletes", member(other, domain.OrgRoleAdmin), TaskDelete, task(), true},
+
+		// Tasks of another organization
+		{"admin of another org cannot read", Subject{UserID: other, OrgID: orgB, OrgRole: domain.OrgRoleOwner}, TaskRead, task(), false},
+		{"creator in another org cannot update", Subject{UserID: creator, OrgID: orgB, OrgRole: domain.OrgRoleOwner}, TaskUpdate, task(), false},
+		{"user without org cannot read own task", Subject{UserID: creator}, TaskRead, task(), false},
+
+		// Platform admins get no access to organization data
+		{"platform admin cannot read", Subject{UserID: other, Role: domain.RoleAdmin}, TaskRead, task(), false},
+		{"platform admin cannot delete", Subject{UserID: other, Role: domain.RoleAdmin}, TaskDelete, task(), false},
+
+		// The worker
+		{"system reads", System, TaskRead, task(), true},
+		{"system changes status", System, TaskUpdateStatus, task(), true},
+		{"system cannot update", System, TaskUpdate, task(), false},
+		{"system cannot delete", System, TaskDelete, task(), false},
+		{"system cannot create", System, TaskCreate, OrgResource(orgA), false},
+
+		// Organizations
+		{"viewer reads org", member(other, domain.OrgRoleViewer), OrgRead, OrgResource(orgA), true},
+		{"subject without role cannot read org", Subject{UserID: other, OrgID: orgA}, OrgRead, OrgResource(orgA), false},
+		{"viewer cannot read other org", member(other, domain.OrgRoleViewer), OrgRead, OrgResource(orgB), false},
+		{"member cannot manage members", member(other, domain.OrgRoleMember), OrgManageMembers, OrgResource(orgA), false},
+		{"admin manages members", member(other, domain.OrgRoleAdmin), OrgManageMembers, OrgResource(orgA), true},
+		{"admin cannot manage owners", member(other, domain.OrgRoleAdmin), OrgManageOwners, OrgResource(orgA), false},
+		{"owner manages owners", member(other, domain.OrgRoleOwner), OrgManageOwners, OrgResource(orgA), true},
+		{"admin cannot delete org", member(other, domain.OrgRoleAdmin), OrgDelete, OrgResource(orgA), false},
+		{"owner deletes org", member(other, domain.OrgRoleOwner), OrgDelete, OrgResource(orgA), true},
+
+		// Personal resources
+		{"owner updates label", Subject{UserID: creator}, LabelUpdate, OwnedResource(creator), true},
+		{"other user cannot delete label", Subject{UserID: other}, LabelDelete, OwnedResource(creator), false},
+		{"platform admin deletes label", Subject{UserID: other, Role: domain.RoleAdmin}, LabelDelete, OwnedResource(creator), true},
+		{"owner reads workflow", Subject{UserID: creator}, WorkflowRead, OwnedResource(creator), true},
+		{"other user cannot read workflow", Subject{UserID: other}, WorkflowRead, OwnedResource(creator), false},
+		{"member reads workflow shared with org", member(other, domain.OrgRoleViewer), WorkflowRead, Resource{OrgID: orgA, OwnerID: creator}, true},
+		{"member cannot read workflow of other org", member(other, domain.OrgRoleOwner), WorkflowRead, Resource{OrgID: orgB, OwnerID: creator}, false},
+		{"member cannot delete workflow shared with org", member(other, domain.OrgRoleOwner), WorkflowDelete, Resource{OrgID: orgA, OwnerID: creator}, false},
+		{"system cannot delete workflow", System, WorkflowDelete, OwnedResource(""), false},
+
+		// Anything not in the table is denied
+		{"unknown action", member(other, domain.OrgRoleOwner), Action("task:archive"), task(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Can(tt.subject, tt.action, tt.res); got != tt.want {
+				t.Errorf("Can(%s) = %v, want %v", tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	p := New()
+
+	tests := []struct {
+		name    string
+		subject Subject
+		res     Resource
+		actions []Action
+		want    []Action
+	}{
+		{
+			name:    "viewer in org",
+			subject: member(other, domain.OrgRoleViewer),
+			res:     OrgResource(orgA),
+			actions: OrgActions,
+			want:    []Action{TaskReadAll, OrgRead},
+		},
+		{
+			name:    "admin in org",
+			subject: member(other, domain.OrgRoleAdmin),
+			res:     OrgResource(orgA),
+			actions: OrgActions,
+			want:    []Action{TaskCreate, TaskReadAll, OrgRead, OrgManageMembers},
+		},
+		{
+			name:    "assignee on task",
+			subject: member(other, domain.OrgRoleMember),
+			res:     task(other),
+			actions: TaskActions,
+			want:    []Action{TaskRead, TaskUpdateStatus},
+		},
+		{
+			name:    "member in org",
+			subject: member(other, domain.OrgRoleMember),
+			res:     OrgResource(orgA),
+			actions: OrgActions,
+			want:    []Action{TaskCreate, TaskReadAll, OrgRead},
+		},
+		{
+			name:    "creator on task",
+			subject: member(creator, domain.OrgRoleMember),
+			res:     task(),
+			actions: TaskActions,
+			want:    TaskActions,
+		},
+		{
+			name:    "viewer on task",
+			subject: member(other, domain.OrgRoleViewer),
+			res:     task(),
+			actions: TaskActions,
+			want:    []Action{TaskRead},
+		},
+		{
+			name:    "stranger on task",
+			subject: Subject{UserID: other, OrgID: orgB, OrgRole: domain.OrgRoleOwner},
+			res:     task(),
+			actions: TaskActions,
+			want:    []Action{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Allowed(tt.subject, tt.res, tt.actions...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRolesOnlyGainPermissions checks that promoting a user never takes away
+// anything they could do or see before.
+func TestRolesOnlyGainPermissions(t *testing.T) {
+	p := New()
+	roles := []domain.OrgRole{domain.OrgRoleViewer, domain.OrgRoleMember, domain.OrgRoleAdmin, domain.OrgRoleOwner}
+	resources := map[string]Resource{
+		"org":              OrgResource(orgA),
+		"unrelated task":   task(),
+		"assigned task":    task(other),
+		"own task":         {OrgID: orgA, OwnerID: other},
+		"other org's task": {OrgID: orgB, OwnerID: other, AssigneeIDs: []string{other}},
+	}
+	actions := append(append([]Action{}, OrgActions...), TaskActions...)
+
+	for name, res := range resources {
+		for i := 1; i < len(roles); i++ {
+			lower := p.Allowed(member(other, roles[i-1]), res, actions...)
+			higher := p.Allowed(member(other, roles[i]), res, actions...)
+			for _, action := range lower {
+				if !p.Can(member(other, roles[i]), action, res) {
+					t.Errorf("%s: %s may %s but %s may not (%v vs %v)", name, roles[i-1], action, roles[i], lower, higher)
+				}
+			}
+		}
+	}
+}
+
+func TestTaskResource(t *testing.T) {
+	tk := &domain.Task{
+		UserID:    creator,
+		OrgID:     orgA,
+		Assignees: []domain.Assignee{{UserID: other}},
+	}
+
+	want := Resource{OrgID: orgA, OwnerID: creator, AssigneeIDs: []string{other}}
+	if got := TaskResource(tk); !reflect.DeepEqual(got, want) {
+		t.Errorf("TaskResource() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"task-management-api/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+type AccessTokenRepository interface {
+	Create(token *domain.PersonalAccessToken) error
+	FindByID(id string) (*domain.PersonalAccessToken, error)
+	FindByHash(hash string) (*domain.PersonalAccessToken, error)
+	FindByUser(userID string) ([]domain.PersonalAccessToken, error)
+	Revoke(id string, at time.Time) error
+	RevokeAllForUser(userID string, at time.Time) error
+	Touch(id string, at time.Time) error
+}
+
+type accessTokenRepository struct {
+	db *sql.DB
+}
+
+func NewAccessTokenRepository(db *sql.DB) AccessTokenRepository {
+	return &accessTokenRepository{db: db}
+}
+
+const accessTokenColumns = "id, user_id, org_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at"
+
+func (r *accessTokenRepository) Create(token *domain.PersonalAccessToken) error {
+	query := `
+		INSERT INTO personal_access_tokens (id, user_id, org_id, name, token_prefix, token_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	scopes := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = string(scope)
+	}
+	_, err := r.db.Exec(
+		query,
+		token.ID,
+		token.UserID,
+		token.OrgID,
+		token.Name,
+		token.Prefix,
+		token.TokenHash,
+		pq.Array(scopes),
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create access token: %w", err)
+	}
+	return nil
+}
+
+func (r *accessTokenRepository) FindByID(id string) (*domain.PersonalAccessToken, error) {
+	return r.findOne("id", id)
+}
+
+func (r *accessTokenRepository) FindByHash(hash string) (*domain.PersonalAccessToken, error) {
+	return r.findOne("token_hash", hash)
+}
+
+func (r *accessTokenRepository) findOne(column, value string) (*domain.PersonalAccessToken, error) {
+	query := "SELECT " + accessTokenColumns + " FROM personal_access_tokens WHERE " + column + " = $1"
+	token := &domain.PersonalAccessToken{}
+	err := scanAccessToken(r.db.QueryRow(query, value), token)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find access token: %w", err)
+	}
+	return token, nil
+}
+
+// FindByUser returns the tokens of the user that were not revoked, newest
+// first. Expired tokens are included so that users can see why a script
+// stopped working.
+func (r *accessTokenRepository) FindByUser(userID string) ([]domain.PersonalAccessToken, error) {
+	query := "SELECT " + accessTokenColumns + `
+		FROM personal_access_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC, id
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find access tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []domain.PersonalAccessToken{}
+	for rows.Next() {
+		var token domain.PersonalAccessToken
+		if err := scanAccessToken(rows, &token); err != nil {
+			return nil, fmt.Errorf("failed to scan access token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (r *accessTokenRepository) Revoke(id string, at time.Time) error {
+	query := "UPDATE personal_access_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL"
+	if _, err := r.db.Exec(query, at, id); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+func (r *accessTokenRepository) RevokeAllForUser(userID string, at time.Time) error {
+	query := "UPDATE personal_access_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL"
+	if _, err := r.db.Exec(query, at, userID); err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+	return nil
+}
+
+// Touch records the use of the token. To spare a write on every request of
+// a busy script, last_used_at is only moved once a minute.
+func (r *accessTokenRepository) Touch(id string, at time.Time) error {
+	query := `
+		UPDATE personal_access_tokens SET last_used_at = $1
+		WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $1 - INTERVAL '1 minute')
+	`
+	if _, err := r.db.Exec(query, at, id); err != nil {
+		return fmt.Errorf("failed to record access token use: %w", err)
+	}
+	return nil
+}
+
+func scanAccessToken(row rowScanner, token *domain.PersonalAccessToken) error {
+	var scopes []string
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.OrgID,
+		&token.Name,
+		&token.Prefix,
+		&token.TokenHash,
+		pq.Array(&scopes),
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	token.Scopes = make([]domain.TokenScope, len(scopes))
+	for i, scope := range scopes {
+		token.Scopes[i] = domain.TokenScope(scope)
+	}
+	return nil
+}
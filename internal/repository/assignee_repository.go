@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"task-management-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type AssigneeRepository interface {
+	Set(taskID string, userIDs []string, changedBy string) error
+	FindByTaskIDs(taskIDs []string) (map[string][]domain.Assignee, error)
+	FindHistory(taskID string) ([]domain.AssignmentChange, error)
+}
+
+type assigneeRepository struct {
+	db *sql.DB
+}
+
+func NewAssigneeRepository(db *sql.DB) AssigneeRepository {
+	return &assigneeRepository{db: db}
+}
+
+// Set replaces the assignees of a task with userIDs and records every user
+// added or removed in the assignment history, in one transaction.
+func (r *assigneeRepository) Set(taskID string, userIDs []string, changedBy string) error {
+	// pq encodes a nil slice as NULL, which ANY() would never match
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	removed, err := tx.Query(`
+		DELETE FROM task_assignees
+		WHERE task_id = $1 AND NOT (user_id = ANY($2))
+		RETURNING user_id
+	`, taskID, pq.Array(userIDs))
+	if err != nil {
+		return fmt.Errorf("failed to remove assignees: %w", err)
+	}
+	removedIDs, err := scanIDs(removed)
+	if err != nil {
+		return fmt.Errorf("failed to remove assignees: %w", err)
+	}
+
+	added, err := tx.Query(`
+		INSERT INTO task_assignees (task_id, user_id, assigned_by, assigned_at)
+		SELECT $1, u, $3, $4 FROM unnest($2::uuid[]) AS u
+		ON CONFLICT DO NOTHING
+		RETURNING user_id
+	`, taskID, pq.Array(userIDs), changedBy, now)
+	if err != nil {
+		return fmt.Errorf("failed to add assignees: %w", err)
+	}
+	addedIDs, err := scanIDs(added)
+	if err != nil {
+		return fmt.Errorf("failed to add assignees: %w", err)
+	}
+
+	history := `
+		INSERT INTO task_assignment_history (id, task_id, user_id, action, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	record := func(userID string, action domain.AssignmentAction) error {
+		_, err := tx.Exec(history, uuid.New().String(), taskID, userID, action, changedBy, now)
+		return err
+	}
+	for _, id := range removedIDs {
+		if err := record(id, domain.AssignmentRemoved); err != nil {
+			return fmt.Errorf("failed to record assignment change: %w", err)
+		}
+	}
+	for _, id := range addedIDs {
+		if err := record(id, domain.AssignmentAdded); err != nil {
+			return fmt.Errorf("failed to record assignment change: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindByTaskIDs loads the assignees of many tasks in a single query, keyed by
+// task ID.
+func (r *assigneeRepository) FindByTaskIDs(taskIDs []string) (map[string][]domain.Assignee, error) {
+	assignees := make(map[string][]domain.Assignee)
+	if len(taskIDs) == 0 {
+		return assignees, nil
+	}
+
+	query := `
+		SELECT task_id, user_id, assigned_by, assigned_at
+		FROM task_assignees
+		WHERE task_id = ANY($1)
+		ORDER BY assigned_at, user_id
+	`
+	rows, err := r.db.Query(query, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find assignees: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		var assignee domain.Assignee
+		if err := rows.Scan(&taskID, &assignee.UserID, &assignee.AssignedBy, &assignee.AssignedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan assignee: %w", err)
+		}
+		assignees[taskID] = append(assignees[taskID], assignee)
+	}
+
+	return assignees, rows.Err()
+}
+
+// FindHistory returns the assignment changes of a task, newest first.
+func (r *assigneeRepository) FindHistory(taskID string) ([]domain.AssignmentChange, error) {
+	query := `
+		SELECT id, task_id, user_id, action, changed_by, changed_at
+		FROM task_assignment_history
+		WHERE task_id = $1
+		ORDER BY changed_at DESC, id
+	`
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find assignment history: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []domain.AssignmentChange{}
+	for rows.Next() {
+		var change domain.AssignmentChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.TaskID,
+			&change.UserID,
+			&change.Action,
+			&change.ChangedBy,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+func scanIDs(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"task-management-api/internal/domain"
+)
+
+type DependencyRepository interface {
+	Add(taskID, blockerID string) error
+	Remove(taskID, blockerID string) error
+	FindBlockers(taskID string) ([]domain.Task, error)
+}
+
+type dependencyRepository struct {
+	db *sql.DB
+}
+
+func NewDependencyRepository(db *sql.DB) DependencyRepository {
+	return &dependencyRepository{db: db}
+}
+
+// Add records that taskID is blocked by blockerID. It returns
+// domain.ErrDependencyCycle if blockerID already depends on taskID, directly
+// or transitively. The table lock serialises concurrent additions so that two
+// edges that only form a cycle together cannot both be inserted.
+func (r *dependencyRepository) Add(taskID, blockerID string) error {
+	if taskID == blockerID {
+		return domain.ErrDependencyCycle
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("LOCK TABLE task_dependencies IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return fmt.Errorf("failed to lock dependencies: %w", err)
+	}
+
+	// Walk everything blockerID waits for; reaching taskID closes a cycle
+	query := `
+		WITH RECURSIVE blockers AS (
+			SELECT blocked_by_id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT d.blocked_by_id FROM task_dependencies d
+			JOIN blockers b ON d.task_id = b.blocked_by_id
+		)
+		SELECT EXISTS (SELECT 1 FROM blockers WHERE blocked_by_id = $2)
+	`
+	var cycle bool
+	if err := tx.QueryRow(query, blockerID, taskID).Scan(&cycle); err != nil {
+		return fmt.Errorf("failed to check dependency cycle: %w", err)
+	}
+	if cycle {
+		return domain.ErrDependencyCycle
+	}
+
+	insert := `
+		INSERT INTO task_dependencies (task_id, blocked_by_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(insert, taskID, blockerID); err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *dependencyRepository) Remove(taskID, blockerID string) error {
+	query := "DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2"
+	_, err := r.db.Exec(query, taskID, blockerID)
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	return nil
+}
+
+// FindBlockers returns the tasks taskID is directly blocked by, completed or
+// not.
+func (r *dependencyRepository) FindBlockers(taskID string) ([]domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id IN (SELECT blocked_by_id FROM task_dependencies WHERE task_id = $1)
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blocking tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blocking tasks: %w", err)
+	}
+	return tasks, nil
+}
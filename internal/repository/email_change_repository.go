@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"task-management-api/internal/domain"
+)
+
+type EmailChangeRepository interface {
+	Save(change *domain.EmailChange) error
+	FindByHash(hash string) (*domain.EmailChange, error)
+	Delete(userID string) error
+}
+
+type emailChangeRepository struct {
+	db *sql.DB
+}
+
+func NewEmailChangeRepository(db *sql.DB) EmailChangeRepository {
+	return &emailChangeRepository{db: db}
+}
+
+// Save stores the change, replacing a pending change of the same user.
+func (r *emailChangeRepository) Save(change *domain.EmailChange) error {
+	query := `
+		INSERT INTO email_changes (user_id, new_email, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET new_email = EXCLUDED.new_email, token_hash = EXCLUDED.token_hash,
+			expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`
+	_, err := r.db.Exec(query, change.UserID, change.NewEmail, change.TokenHash, change.ExpiresAt, change.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save email change: %w", err)
+	}
+	return nil
+}
+
+func (r *emailChangeRepository) FindByHash(hash string) (*domain.EmailChange, error) {
+	query := `
+		SELECT user_id, new_email, token_hash, expires_at, created_at
+		FROM email_changes
+		WHERE token_hash = $1
+	`
+	change := &domain.EmailChange{}
+	err := r.db.QueryRow(query, hash).Scan(&change.UserID, &change.NewEmail, &change.TokenHash, &change.ExpiresAt, &change.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email change: %w", err)
+	}
+	return change, nil
+}
+
+func (r *emailChangeRepository) Delete(userID string) error {
+	query := "DELETE FROM email_changes WHERE user_id = $1"
+	if _, err := r.db.Exec(query, userID); err != nil {
+		return fmt.Errorf("failed to delete email change: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"task-management-api/internal/domain"
+)
+
+type EmailVerificationRepository interface {
+	Save(verification *domain.EmailVerification) error
+	FindByUser(userID string) (*domain.EmailVerification, error)
+	FindByHash(hash string) (*domain.EmailVerification, error)
+	Delete(userID string) error
+}
+
+type emailVerificationRepository struct {
+	db *sql.DB
+}
+
+func NewEmailVerificationRepository(db *sql.DB) EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+const emailVerificationColumns = "user_id, email, token_hash, expires_at, created_at"
+
+func scanEmailVerification(row rowScanner, v *domain.EmailVerification) error {
+	return row.Scan(&v.UserID, &v.Email, &v.TokenHash, &v.ExpiresAt, &v.CreatedAt)
+}
+
+// Save stores the verification, replacing a pending one of the same user.
+func (r *emailVerificationRepository) Save(v *domain.EmailVerification) error {
+	query := `
+		INSERT INTO email_verifications (user_id, email, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = EXCLUDED.email, token_hash = EXCLUDED.token_hash,
+			expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`
+	_, err := r.db.Exec(query, v.UserID, v.Email, v.TokenHash, v.ExpiresAt, v.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save email verification: %w", err)
+	}
+	return nil
+}
+
+func (r *emailVerificationRepository) FindByUser(userID string) (*domain.EmailVerification, error) {
+	return r.findOne("SELECT "+emailVerificationColumns+" FROM email_verifications WHERE user_id = $1", userID)
+}
+
+func (r *emailVerificationRepository) FindByHash(hash string) (*domain.EmailVerification, error) {
+	return r.findOne("SELECT "+emailVerificationColumns+" FROM email_verifications WHERE token_hash = $1", hash)
+}
+
+func (r *emailVerificationRepository) findOne(query string, arg string) (*domain.EmailVerification, error) {
+	v := &domain.EmailVerification{}
+	err := scanEmailVerification(r.db.QueryRow(query, arg), v)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email verification: %w", err)
+	}
+	return v, nil
+}
+
+func (r *emailVerificationRepository) Delete(userID string) error {
+	query := "DELETE FROM email_verifications WHERE user_id = $1"
+	if _, err := r.db.Exec(query, userID); err != nil {
+		return fmt.Errorf("failed to delete email verification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"task-management-api/internal/domain"
+)
+
+type InvitationRepository interface {
+	Create(invitation *domain.AdminInvitation) error
+	FindByID(id string) (*domain.AdminInvitation, error)
+	FindByHash(hash string) (*domain.AdminInvitation, error)
+	FindPending() ([]domain.AdminInvitation, error)
+	Accept(id string, at time.Time) (bool, error)
+	Delete(id string) error
+}
+
+type invitationRepository struct {
+	db *sql.DB
+}
+
+func NewInvitationRepository(db *sql.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+const invitationColumns = "id, email, token_hash, invited_by, expires_at, accepted_at, created_at"
+
+func (r *invitationRepository) Create(invitation *domain.AdminInvitation) error {
+	query := `
+		INSERT INTO admin_invitations (id, email, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(
+		query,
+		invitation.ID,
+		invitation.Email,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+		invitation.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+	return nil
+}
+
+func (r *invitationRepository) FindByID(id string) (*domain.AdminInvitation, error) {
+	return r.findOne("id", id)
+}
+
+func (r *invitationRepository) FindByHash(hash string) (*domain.AdminInvitation, error) {
+	return r.findOne("token_hash", hash)
+}
+
+func (r *invitationRepository) findOne(column, value string) (*domain.AdminInvitation, error) {
+	query := "SELECT " + invitationColumns + " FROM admin_invitations WHERE " + column + " = $1"
+	invitation := &domain.AdminInvitation{}
+	err := scanInvitation(r.db.QueryRow(query, value), invitation)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	return invitation, nil
+}
+
+// FindPending returns the invitations that were neither accepted nor have
+// expired, newest first.
+func (r *invitationRepository) FindPending() ([]domain.AdminInvitation, error) {
+	query := "SELECT " + invitationColumns + `
+		FROM admin_invitations
+		WHERE accepted_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC, id
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []domain.AdminInvitation{}
+	for rows.Next() {
+		var invitation domain.AdminInvitation
+		if err := scanInvitation(rows, &invitation); err != nil {
+			return nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+// Accept marks a pending invitation as accepted. It reports false if the
+// invitation was accepted already, so a token cannot be used twice.
+func (r *invitationRepository) Accept(id string, at time.Time) (bool, error) {
+	query := "UPDATE admin_invitations SET accepted_at = $1 WHERE id = $2 AND accepted_at IS NULL"
+	result, err := r.db.Exec(query, at, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *invitationRepository) Delete(id string) error {
+	query := "DELETE FROM admin_invitations WHERE id = $1"
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to delete invitation: %w", err)
+	}
+	return nil
+}
+
+func scanInvitation(row rowScanner, invitation *domain.AdminInvitation) error {
+	return row.Scan(
+		&invitation.ID,
+		&invitation.Email,
+		&invitation.TokenHash,
+		&invitation.InvitedBy,
+		&invitation.ExpiresAt,
+		&invitation.AcceptedAt,
+		&invitation.CreatedAt,
+	)
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"task-management-api/internal/domain"
+)
+
+type JobRepository interface {
+	Enqueue(job *domain.Job) error
+	Cancel(jobType domain.JobType, taskID string) ([]string, error)
+	ClaimDue(limit int) ([]domain.Job, error)
+	FindUpcoming(until time.Time) ([]domain.Job, error)
+	Complete(id string, claimedRunAt time.Time) (*time.Time, error)
+	Retry(id string, claimedRunAt, runAt time.Time, lastError string) (time.Time, error)
+	Fail(id string, claimedRunAt time.Time, lastError string) (*time.Time, error)
+	RequeueStale(lockTimeout time.Duration) (int64, error)
+	DeleteFinished(before time.Time) (int64, error)
+}
+
+type jobRepository struct {
+	db *sql.DB
+}
+
+func NewJobRepository(db *sql.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// Enqueue stores a job. If an equivalent job is already queued or running for
+// the same task, that job is moved to the new run-at time instead and its ID
+// is written back to job.ID. A running job moved this way is queued again
+// when it finishes, see Complete.
+func (r *jobRepository) Enqueue(job *domain.Job) error {
+	query := `
+		INSERT INTO jobs (id, type, task_id, status, run_at, attempts, max_attempts, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (type, task_id) WHERE status IN ('queued', 'running')
+		DO UPDATE SET run_at = EXCLUDED.run_at, updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+	err := r.db.QueryRow(
+		query,
+		job.ID,
+		job.Type,
+		job.TaskID,
+		job.Status,
+		job.RunAt,
+		job.Attempts,
+		job.MaxAttempts,
+		job.CreatedAt,
+		job.UpdatedAt,
+	).Scan(&job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return nil
+}
+
+// Cancel removes a queued job of the given type for the task and returns the
+// IDs of the removed jobs. Jobs that are already running are left to finish.
+func (r *jobRepository) Cancel(jobType domain.JobType, taskID string) ([]string, error) {
+	query := "DELETE FROM jobs WHERE type = $1 AND task_id = $2 AND status = $3 RETURNING id"
+	rows, err := r.db.Query(query, jobType, taskID, domain.JobQueued)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel job: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// ClaimDue locks up to limit due jobs for this process. SKIP LOCKED lets
+// several replicas poll the same table without handing out a job twice.
+func (r *jobRepository) ClaimDue(limit int) ([]domain.Job, error) {
+	query := `
+		UPDATE jobs
+		SET status = $1, attempts = attempts + 1, locked_at = $2, updated_at = $2
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE status = $3 AND run_at <= $2
+			ORDER BY run_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, type, task_id, status, run_at, attempts, max_attempts, last_error, locked_at, created_at, updated_at
+	`
+	rows, err := r.db.Query(query, domain.JobRunning, time.Now(), domain.JobQueued, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []domain.Job
+	for rows.Next() {
+		var job domain.Job
+		if err := rows.Scan(
+			&job.ID,
+			&job.Type,
+			&job.TaskID,
+			&job.Status,
+			&job.RunAt,
+			&job.Attempts,
+			&job.MaxAttempts,
+			&job.LastError,
+			&job.LockedAt,
+			&job.CreatedAt,
+			&job.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// FindUpcoming returns queued jobs that become due before until, so they can
+// be timed in memory instead of being discovered by polling.
+func (r *jobRepository) FindUpcoming(until time.Time) ([]domain.Job, error) {
+	query := `
+		SELECT id, type, task_id, status, run_at, attempts, max_attempts, last_error, locked_at, created_at, updated_at
+		FROM jobs
+		WHERE status = $1 AND run_at <= $2
+		ORDER BY run_at
+	`
+	rows, err := r.db.Query(query, domain.JobQueued, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find upcoming jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []domain.Job
+	for rows.Next() {
+		var job domain.Job
+		if err := rows.Scan(
+			&job.ID,
+			&job.Type,
+			&job.TaskID,
+			&job.Status,
+			&job.RunAt,
+			&job.Attempts,
+			&job.MaxAttempts,
+			&job.LastError,
+			&job.LockedAt,
+			&job.CreatedAt,
+			&job.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// Complete marks a job as done. claimedRunAt is the run-at time the job was
+// claimed with: if Enqueue moved the job while it ran, it is queued again for
+// the new time with fresh attempts, and that time is returned.
+func (r *jobRepository) Complete(id string, claimedRunAt time.Time) (*time.Time, error) {
+	query := `
+		UPDATE jobs
+		SET status = CASE WHEN run_at = $1 THEN $2 ELSE $3 END,
+			attempts = CASE WHEN run_at = $1 THEN attempts ELSE 0 END,
+			locked_at = NULL, updated_at = $4
+		WHERE id = $5
+		RETURNING status, run_at
+	`
+	requeued, err := r.finish(query, claimedRunAt, domain.JobDone, domain.JobQueued, time.Now(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete job: %w", err)
+	}
+	return requeued, nil
+}
+
+// Retry queues a failed job again at runAt. A job that Enqueue moved while it
+// ran keeps the new time and starts over with fresh attempts. The time the
+// job will run at is returned.
+func (r *jobRepository) Retry(id string, claimedRunAt, runAt time.Time, lastError string) (time.Time, error) {
+	query := `
+		UPDATE jobs
+		SET status = $1,
+			run_at = CASE WHEN run_at = $2 THEN $3 ELSE run_at END,
+			attempts = CASE WHEN run_at = $2 THEN attempts ELSE 0 END,
+			last_error = $4, locked_at = NULL, updated_at = $5
+		WHERE id = $6
+		RETURNING run_at
+	`
+	var next time.Time
+	err := r.db.QueryRow(query, domain.JobQueued, claimedRunAt, runAt, lastError, time.Now(), id).Scan(&next)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to reschedule job: %w", err)
+	}
+	return next, nil
+}
+
+// Fail marks a job as dead after its last attempt. Like Complete, it queues
+// the job again instead if Enqueue moved it while it ran.
+func (r *jobRepository) Fail(id string, claimedRunAt time.Time, lastError string) (*time.Time, error) {
+	query := `
+		UPDATE jobs
+		SET status = CASE WHEN run_at = $1 THEN $2 ELSE $3 END,
+			attempts = CASE WHEN run_at = $1 THEN attempts ELSE 0 END,
+			last_error = $6, locked_at = NULL, updated_at = $4
+		WHERE id = $5
+		RETURNING status, run_at
+	`
+	requeued, err := r.finish(query, claimedRunAt, domain.JobDead, domain.JobQueued, time.Now(), id, lastError)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark job as dead: %w", err)
+	}
+	return requeued, nil
+}
+
+// finish runs an update returning the job's status and run-at time, and
+// returns the run-at time if the job ended up queued again.
+func (r *jobRepository) finish(query string, args ...interface{}) (*time.Time, error) {
+	var status domain.JobStatus
+	var runAt time.Time
+	if err := r.db.QueryRow(query, args...).Scan(&status, &runAt); err != nil {
+		return nil, err
+	}
+	if status != domain.JobQueued {
+		return nil, nil
+	}
+	return &runAt, nil
+}
+
+// RequeueStale returns jobs to the queue whose worker stopped reporting back,
+// typically because the process crashed while running them.
+func (r *jobRepository) RequeueStale(lockTimeout time.Duration) (int64, error) {
+	query := `
+		UPDATE jobs
+		SET status = $1, locked_at = NULL, updated_at = $2
+		WHERE status = $3 AND locked_at < $4
+	`
+	result, err := r.db.Exec(query, domain.JobQueued, time.Now(), domain.JobRunning, time.Now().Add(-lockTimeout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale jobs: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale jobs: %w", err)
+	}
+	return rows, nil
+}
+
+// DeleteFinished removes done and dead jobs that finished before the given
+// time and returns how many were removed.
+func (r *jobRepository) DeleteFinished(before time.Time) (int64, error) {
+	query := "DELETE FROM jobs WHERE status IN ($1, $2) AND updated_at < $3"
+	result, err := r.db.Exec(query, domain.JobDone, domain.JobDead, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished jobs: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished jobs: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"task-management-api/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+type LabelRepository interface {
+	Create(label *domain.Label) error
+	FindByID(id string) (*domain.Label, error)
+	FindByName(userID, name string) (*domain.Label, error)
+	FindByUser(userID string) ([]domain.Label, error)
+	FindByTaskIDs(taskIDs []string) (map[string][]domain.Label, error)
+	Update(label *domain.Label) error
+	Delete(id string) error
+	Attach(taskID, labelID string) error
+	Detach(taskID, labelID string) error
+}
+
+type labelRepository struct {
+	db *sql.DB
+}
+
+func NewLabelRepository(db *sql.DB) LabelRepository {
+	return &labelRepository{db: db}
+}
+
+func (r *labelRepository) Create(label *domain.Label) error {
+	query := `
+		INSERT INTO labels (id, user_id, name, color, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(
+		query,
+		label.ID,
+		label.UserID,
+		label.Name,
+		label.Color,
+		label.CreatedAt,
+		label.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create label: %w", err)
+	}
+	return nil
+}
+
+func (r *labelRepository) FindByID(id string) (*domain.Label, error) {
+	query := `
+		SELECT id, user_id, name, color, created_at, updated_at
+		FROM labels
+		WHERE id = $1
+	`
+	label := &domain.Label{}
+	err := r.db.QueryRow(query, id).Scan(
+		&label.ID,
+		&label.UserID,
+		&label.Name,
+		&label.Color,
+		&label.CreatedAt,
+		&label.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find label: %w", err)
+	}
+	return label, nil
+}
+
+func (r *labelRepository) FindByName(userID, name string) (*domain.Label, error) {
+	query := `
+		SELECT id, user_id, name, color, created_at, updated_at
+		FROM labels
+		WHERE user_id = $1 AND name = $2
+	`
+	label := &domain.Label{}
+	err := r.db.QueryRow(query, userID, name).Scan(
+		&label.ID,
+		&label.UserID,
+		&label.Name,
+		&label.Color,
+		&label.CreatedAt,
+		&label.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find label: %w", err)
+	}
+	return label, nil
+}
+
+func (r *labelRepository) FindByUser(userID string) ([]domain.Label, error) {
+	query := `
+		SELECT id, user_id, name, color, created_at, updated_at
+		FROM labels
+		WHERE user_id = $1
+		ORDER BY name
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := []domain.Label{}
+	for rows.Next() {
+		var label domain.Label
+		if err := rows.Scan(
+			&label.ID,
+			&label.UserID,
+			&label.Name,
+			&label.Color,
+			&label.CreatedAt,
+			&label.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan label: %w", err)
+		}
+		labels = append(labels, label)
+	}
+
+	return labels, nil
+}
+
+// FindByTaskIDs loads the labels of many tasks in a single query, keyed by
+// task ID, so listing tasks does not issue one query per task.
+func (r *labelRepository) FindByTaskIDs(taskIDs []string) (map[string][]domain.Label, error) {
+	labels := make(map[string][]domain.Label)
+	if len(taskIDs) == 0 {
+		return labels, nil
+	}
+
+	query := `
+		SELECT tl.task_id, l.id, l.user_id, l.name, l.color, l.created_at, l.updated_at
+		FROM task_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		WHERE tl.task_id = ANY($1)
+		ORDER BY l.name
+	`
+	rows, err := r.db.Query(query, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		var label domain.Label
+		if err := rows.Scan(
+			&taskID,
+			&label.ID,
+			&label.UserID,
+			&label.Name,
+			&label.Color,
+			&label.CreatedAt,
+			&label.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan task label: %w", err)
+		}
+		labels[taskID] = append(labels[taskID], label)
+	}
+
+	return labels, nil
+}
+
+func (r *labelRepository) Update(label *domain.Label) error {
+	query := `
+		UPDATE labels
+		SET name = $1, color = $2, updated_at = $3
+		WHERE id = $4
+	`
+	_, err := r.db.Exec(query, label.Name, label.Color, label.UpdatedAt, label.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update label: %w", err)
+	}
+	return nil
+}
+
+func (r *labelRepository) Delete(id string) error {
+	query := "DELETE FROM labels WHERE id = $1"
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+	return nil
+}
+
+func (r *labelRepository) Attach(taskID, labelID string) error {
+	query := `
+		INSERT INTO task_labels (task_id, label_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.Exec(query, taskID, labelID)
+	if err != nil {
+		return fmt.Errorf("failed to attach label: %w", err)
+	}
+	return nil
+}
+
+func (r *labelRepository) Detach(taskID, labelID string) error {
+	query := "DELETE FROM task_labels WHERE task_id = $1 AND label_id = $2"
+	_, err := r.db.Exec(query, taskID, labelID)
+	if err != nil {
+		return fmt.Errorf("failed to detach label: %w", err)
+	}
+	return nil
+}
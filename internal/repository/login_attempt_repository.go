@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"task-management-api/internal/domain"
+)
+
+type LoginAttemptRepository interface {
+	Find(kind domain.AttemptKind, key string) (*domain.LoginAttempts, error)
+	RecordFailure(kind domain.AttemptKind, key string, at time.Time, window time.Duration) (*domain.LoginAttempts, error)
+	Lock(kind domain.AttemptKind, key string, at, until time.Time) error
+	Reset(kind domain.AttemptKind, key string) error
+}
+
+type loginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+const loginAttemptColumns = "kind, key, failures, window_start, last_failure_at, locked_until"
+
+func scanLoginAttempts(row rowScanner, a *domain.LoginAttempts) error {
+	return row.Scan(&a.Kind, &a.Key, &a.Failures, &a.WindowStart, &a.LastFailureAt, &a.LockedUntil)
+}
+
+func (r *loginAttemptRepository) Find(kind domain.AttemptKind, key string) (*domain.LoginAttempts, error) {
+	query := "SELECT " + loginAttemptColumns + " FROM login_attempts WHERE kind = $1 AND key = $2"
+	attempts := &domain.LoginAttempts{}
+	err := scanLoginAttempts(r.db.QueryRow(query, kind, key), attempts)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find login attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// RecordFailure counts a failed login and returns the updated counter. A
+// counter whose window has passed starts again at one.
+func (r *loginAttemptRepository) RecordFailure(kind domain.AttemptKind, key string, at time.Time, window time.Duration) (*domain.LoginAttempts, error) {
+	query := `
+		INSERT INTO login_attempts (kind, key, failures, window_start, last_failure_at)
+		VALUES ($1, $2, 1, $3, $3)
+		ON CONFLICT (kind, key) DO UPDATE
+		SET failures = CASE WHEN login_attempts.window_start < $4 THEN 1 ELSE login_attempts.failures + 1 END,
+			window_start = CASE WHEN login_attempts.window_start < $4 THEN $3 ELSE login_attempts.window_start END,
+			last_failure_at = $3
+		RETURNING ` + loginAttemptColumns
+	attempts := &domain.LoginAttempts{}
+	if err := scanLoginAttempts(r.db.QueryRow(query, kind, key, at, at.Add(-window)), attempts); err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	return attempts, nil
+}
+
+// Lock blocks logins from at until the given time. The failures counted so
+// far are cleared, so counting starts over once the lock ends.
+func (r *loginAttemptRepository) Lock(kind domain.AttemptKind, key string, at, until time.Time) error {
+	query := `
+		UPDATE login_attempts
+		SET locked_until = $4, failures = 0, window_start = $3
+		WHERE kind = $1 AND key = $2
+	`
+	if _, err := r.db.Exec(query, kind, key, at, until); err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) Reset(kind domain.AttemptKind, key string) error {
+	query := "DELETE FROM login_attempts WHERE kind = $1 AND key = $2"
+	if _, err := r.db.Exec(query, kind, key); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
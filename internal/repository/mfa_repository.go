@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"task-management-api/internal/domain"
+)
+
+type MFARepository interface {
+	Save(mfa *domain.UserMFA) error
+	FindByUser(userID string) (*domain.UserMFA, error)
+	Confirm(userID string, at time.Time, codes []domain.RecoveryCode) error
+	UseStep(userID string, step int64) (bool, error)
+	RecordFailure(userID string, at time.Time, window time.Duration) error
+	ResetFailures(userID string) error
+	Delete(userID string) error
+
+	ReplaceRecoveryCodes(userID string, codes []domain.RecoveryCode) error
+	UseRecoveryCode(userID, hash string, at time.Time) (bool, error)
+	CountRecoveryCodes(userID string) (int, error)
+
+	CreateChallenge(challenge *domain.MFAChallenge) error
+	FindChallengeByHash(hash string) (*domain.MFAChallenge, error)
+	AddChallengeAttempt(id string) error
+	ConsumeChallenge(id string) (bool, error)
+	DeleteExpiredChallenges(now time.Time) (int64, error)
+}
+
+type mfaRepository struct {
+	db *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+// Save stores an unconfirmed second factor, replacing a previous one.
+func (r *mfaRepository) Save(mfa *domain.UserMFA) error {
+	query := `
+		INSERT INTO user_mfa (user_id, secret, confirmed_at, last_used_step, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, confirmed_at = EXCLUDED.confirmed_at,
+			last_used_step = EXCLUDED.last_used_step, created_at = EXCLUDED.created_at
+	`
+	_, err := r.db.Exec(query, mfa.UserID, mfa.Secret, mfa.ConfirmedAt, mfa.LastUsedStep, mfa.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save second factor: %w", err)
+	}
+	return nil
+}
+
+func (r *mfaRepository) FindByUser(userID string) (*domain.UserMFA, error) {
+	query := `
+		SELECT user_id, secret, confirmed_at, last_used_step, failed_attempts, last_failure_at, created_at
+		FROM user_mfa
+		WHERE user_id = $1
+	`
+	mfa := &domain.UserMFA{}
+	err := r.db.QueryRow(query, userID).Scan(&mfa.UserID, &mfa.Secret, &mfa.ConfirmedAt, &mfa.LastUsedStep, &mfa.FailedAttempts, &mfa.LastFailureAt, &mfa.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find second factor: %w", err)
+	}
+	return mfa, nil
+}
+
+// Confirm enables the second factor together with its first recovery codes.
+func (r *mfaRepository) Confirm(userID string, at time.Time, codes []domain.RecoveryCode) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE user_mfa SET confirmed_at = $2 WHERE user_id = $1", userID, at); err != nil {
+		return fmt.Errorf("failed to confirm second factor: %w", err)
+	}
+	if err := replaceRecoveryCodes(tx, userID, codes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseStep records the time step of an accepted code. It returns false if
+// that step or a later one was already used, so every code works only once.
+func (r *mfaRepository) UseStep(userID string, step int64) (bool, error) {
+	query := "UPDATE user_mfa SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2"
+	result, err := r.db.Exec(query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record code: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record code: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RecordFailure counts a wrong login code of the user. The count starts again
+// at one if the previous failure is older than window.
+func (r *mfaRepository) RecordFailure(userID string, at time.Time, window time.Duration) error {
+	query := `
+		UPDATE user_mfa
+		SET failed_attempts = CASE WHEN last_failure_at IS NULL OR last_failure_at < $3 THEN 1 ELSE failed_attempts + 1 END,
+			last_failure_at = $2
+		WHERE user_id = $1
+	`
+	if _, err := r.db.Exec(query, userID, at, at.Add(-window)); err != nil {
+		return fmt.Errorf("failed to record wrong code: %w", err)
+	}
+	return nil
+}
+
+func (r *mfaRepository) ResetFailures(userID string) error {
+	query := "UPDATE user_mfa SET failed_attempts = 0, last_failure_at = NULL WHERE user_id = $1"
+	if _, err := r.db.Exec(query, userID); err != nil {
+		return fmt.Errorf("failed to reset wrong codes: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the second factor with its recovery codes and pending
+// login challenges.
+func (r *mfaRepository) Delete(userID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"mfa_challenges", "mfa_recovery_codes", "user_mfa"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE user_id = $1", userID); err != nil {
+			return fmt.Errorf("failed to delete second factor: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(userID string, codes []domain.RecoveryCode) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(tx, userID, codes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, userID string, codes []domain.RecoveryCode) error {
+	if _, err := tx.Exec("DELETE FROM mfa_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	query := `
+		INSERT INTO mfa_recovery_codes (id, user_id, code_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, code := range codes {
+		if _, err := tx.Exec(query, code.ID, userID, code.CodeHash, code.CreatedAt); err != nil {
+			return fmt.Errorf("failed to create recovery code: %w", err)
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode marks the unused code with the hash used. It returns false
+// if there is no such code.
+func (r *mfaRepository) UseRecoveryCode(userID, hash string, at time.Time) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	result, err := r.db.Exec(query, userID, hash, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// CountRecoveryCodes counts the unused recovery codes of the user.
+func (r *mfaRepository) CountRecoveryCodes(userID string) (int, error) {
+	query := "SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL"
+	var count int
+	if err := r.db.QueryRow(query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+func (r *mfaRepository) CreateChallenge(challenge *domain.MFAChallenge) error {
+	query := `
+		INSERT INTO mfa_challenges (id, user_id, token_hash, new_password_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(query, challenge.ID, challenge.UserID, challenge.TokenHash, challenge.NewPasswordHash, challenge.Attempts, challenge.ExpiresAt, challenge.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create login challenge: %w", err)
+	}
+	return nil
+}
+
+func (r *mfaRepository) FindChallengeByHash(hash string) (*domain.MFAChallenge, error) {
+	query := `
+		SELECT id, user_id, token_hash, new_password_hash, attempts, expires_at, created_at
+		FROM mfa_challenges
+		WHERE token_hash = $1
+	`
+	c := &domain.MFAChallenge{}
+	err := r.db.QueryRow(query, hash).Scan(&c.ID, &c.UserID, &c.TokenHash, &c.NewPasswordHash, &c.Attempts, &c.ExpiresAt, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find login challenge: %w", err)
+	}
+	return c, nil
+}
+
+func (r *mfaRepository) AddChallengeAttempt(id string) error {
+	query := "UPDATE mfa_challenges SET attempts = attempts + 1 WHERE id = $1"
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to update login challenge: %w", err)
+	}
+	return nil
+}
+
+// ConsumeChallenge deletes the challenge. It returns false if it was already
+// gone, e.g. used by a concurrent request.
+func (r *mfaRepository) ConsumeChallenge(id string) (bool, error) {
+	result, err := r.db.Exec("DELETE FROM mfa_challenges WHERE id = $1", id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete login challenge: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete login challenge: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// DeleteExpiredChallenges removes login challenges that have expired and
+// returns how many were removed.
+func (r *mfaRepository) DeleteExpiredChallenges(now time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM mfa_challenges WHERE expires_at <= $1", now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired login challenges: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired login challenges: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"task-management-api/internal/domain"
+)
+
+type OrgRepository interface {
+	Create(org *domain.Organization, owner *domain.Membership) error
+	FindByID(id string) (*domain.Organization, error)
+	FindByUser(userID string) ([]domain.Organization, error)
+	Delete(id string) error
+	FindMembership(orgID, userID string) (*domain.Membership, error)
+	FindDefaultMembership(userID string) (*domain.Membership, error)
+	FindMembers(orgID string) ([]domain.Membership, error)
+	AddMember(member *domain.Membership) error
+	UpdateMemberRole(orgID, userID string, role domain.OrgRole) error
+	RemoveMember(orgID, userID string) error
+	CountOwners(orgID string) (int, error)
+}
+
+type orgRepository struct {
+	db *sql.DB
+}
+
+func NewOrgRepository(db *sql.DB) OrgRepository {
+	return &orgRepository{db: db}
+}
+
+// Create stores an organization together with the membership of its first
+// owner, in one transaction.
+func (r *orgRepository) Create(org *domain.Organization, owner *domain.Membership) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO organizations (id, name, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.Exec(query, org.ID, org.Name, org.CreatedBy, org.CreatedAt, org.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	query = `
+		INSERT INTO org_memberships (org_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.Exec(query, owner.OrgID, owner.UserID, owner.Role, owner.CreatedAt); err != nil {
+		return fmt.Errorf("failed to add organization owner: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *orgRepository) FindByID(id string) (*domain.Organization, error) {
+	query := `
+		SELECT id, name, created_by, created_at, updated_at
+		FROM organizations
+		WHERE id = $1
+	`
+	org := &domain.Organization{}
+	err := r.db.QueryRow(query, id).Scan(&org.ID, &org.Name, &org.CreatedBy, &org.CreatedAt, &org.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+	return org, nil
+}
+
+// FindByUser returns the organizations the user is a member of, with the
+// user's role in each, oldest membership first.
+func (r *orgRepository) FindByUser(userID string) ([]domain.Organization, error) {
+	query := `
+		SELECT o.id, o.name, o.created_by, m.role, o.created_at, o.updated_at
+		FROM organizations o
+		JOIN org_memberships m ON m.org_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY m.created_at, o.id
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organizations: %w", err)
+	}
+	defer rows.Close()
+
+	orgs := []domain.Organization{}
+	for rows.Next() {
+		var org domain.Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.CreatedBy, &org.Role, &org.CreatedAt, &org.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan organization: %w", err)
+		}
+		orgs = append(orgs, org)
+	}
+
+	return orgs, rows.Err()
+}
+
+// Delete removes an organization. Its memberships and tasks are deleted by
+// the foreign keys.
+func (r *orgRepository) Delete(id string) error {
+	query := "DELETE FROM organizations WHERE id = $1"
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	return nil
+}
+
+func (r *orgRepository) FindMembership(orgID, userID string) (*domain.Membership, error) {
+	query := `
+		SELECT org_id, user_id, role, created_at
+		FROM org_memberships
+		WHERE org_id = $1 AND user_id = $2
+	`
+	member := &domain.Membership{}
+	err := r.db.QueryRow(query, orgID, userID).Scan(&member.OrgID, &member.UserID, &member.Role, &member.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find membership: %w", err)
+	}
+	return member, nil
+}
+
+// FindDefaultMembership returns the user's oldest membership, which is the
+// organization a new session starts in.
+func (r *orgRepository) FindDefaultMembership(userID string) (*domain.Membership, error) {
+	query := `
+		SELECT org_id, user_id, role, created_at
+		FROM org_memberships
+		WHERE user_id = $1
+		ORDER BY created_at, org_id
+		LIMIT 1
+	`
+	member := &domain.Membership{}
+	err := r.db.QueryRow(query, userID).Scan(&member.OrgID, &member.UserID, &member.Role, &member.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find membership: %w", err)
+	}
+	return member, nil
+}
+
+func (r *orgRepository) FindMembers(orgID string) ([]domain.Membership, error) {
+	query := `
+		SELECT m.org_id, m.user_id, u.email, m.role, m.created_at
+		FROM org_memberships m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.org_id = $1
+		ORDER BY m.created_at, m.user_id
+	`
+	rows, err := r.db.Query(query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []domain.Membership{}
+	for rows.Next() {
+		var member domain.Membership
+		if err := rows.Scan(&member.OrgID, &member.UserID, &member.Email, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+func (r *orgRepository) AddMember(member *domain.Membership) error {
+	query := `
+		INSERT INTO org_memberships (org_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := r.db.Exec(query, member.OrgID, member.UserID, member.Role, member.CreatedAt); err != nil {
+		return fmt.Errorf("failed to add member: %w", err)
+	}
+	return nil
+}
+
+func (r *orgRepository) UpdateMemberRole(orgID, userID string, role domain.OrgRole) error {
+	query := "UPDATE org_memberships SET role = $1 WHERE org_id = $2 AND user_id = $3"
+	if _, err := r.db.Exec(query, role, orgID, userID); err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+	return nil
+}
+
+func (r *orgRepository) RemoveMember(orgID, userID string) error {
+	query := "DELETE FROM org_memberships WHERE org_id = $1 AND user_id = $2"
+	if _, err := r.db.Exec(query, orgID, userID); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	return nil
+}
+
+func (r *orgRepository) CountOwners(orgID string) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM org_memberships WHERE org_id = $1 AND role = $2"
+	if err := r.db.QueryRow(query, orgID, domain.OrgRoleOwner).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count owners: %w", err)
+	}
+	return count, nil
+}
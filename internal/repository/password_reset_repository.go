@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"task-management-api/internal/domain"
+)
+
+type PasswordResetRepository interface {
+	Create(reset *domain.PasswordReset) error
+	CountSince(userID string, since time.Time) (int, error)
+	Consume(hash string, at time.Time) (*domain.PasswordReset, error)
+	InvalidateForUser(userID string, at time.Time) error
+}
+
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(reset *domain.PasswordReset) error {
+	query := `
+		INSERT INTO password_resets (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.Exec(query, reset.ID, reset.UserID, reset.TokenHash, reset.ExpiresAt, reset.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+	return nil
+}
+
+// CountSince counts the resets requested for the user since the given time.
+func (r *passwordResetRepository) CountSince(userID string, since time.Time) (int, error) {
+	query := "SELECT COUNT(*) FROM password_resets WHERE user_id = $1 AND created_at >= $2"
+	var count int
+	if err := r.db.QueryRow(query, userID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count password resets: %w", err)
+	}
+	return count, nil
+}
+
+// Consume marks the reset with the token hash used, as long as it is unused
+// and not expired. It returns nil if there is no such reset, so a token can
+// only be consumed once even by concurrent requests.
+func (r *passwordResetRepository) Consume(hash string, at time.Time) (*domain.PasswordReset, error) {
+	query := `
+		UPDATE password_resets
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+	`
+	reset := &domain.PasswordReset{}
+	err := r.db.QueryRow(query, hash, at).Scan(
+		&reset.ID,
+		&reset.UserID,
+		&reset.TokenHash,
+		&reset.ExpiresAt,
+		&reset.UsedAt,
+		&reset.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume password reset: %w", err)
+	}
+	return reset, nil
+}
+
+// InvalidateForUser marks all unused resets of the user used.
+func (r *passwordResetRepository) InvalidateForUser(userID string, at time.Time) error {
+	query := "UPDATE password_resets SET used_at = $2 WHERE user_id = $1 AND used_at IS NULL"
+	if _, err := r.db.Exec(query, userID, at); err != nil {
+		return fmt.Errorf("failed to invalidate password resets: %w", err)
+	}
+	return nil
+}
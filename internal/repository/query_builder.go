@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryBuilder collects WHERE conditions and their positional arguments for
+// queries assembled from optional filters.
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg registers a query argument and returns its placeholder.
+func (b *queryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *queryBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// whereClause returns the combined conditions prefixed with WHERE, or an
+// empty string when there are none.
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"task-management-api/internal/domain"
+)
+
+type SecurityEventRepository interface {
+	Create(event *domain.SecurityEvent) error
+	FindAll(filter domain.SecurityEventFilter) ([]domain.SecurityEvent, error)
+	Count(filter domain.SecurityEventFilter) (int, error)
+}
+
+type securityEventRepository struct {
+	db *sql.DB
+}
+
+func NewSecurityEventRepository(db *sql.DB) SecurityEventRepository {
+	return &securityEventRepository{db: db}
+}
+
+func (r *securityEventRepository) Create(event *domain.SecurityEvent) error {
+	query := `
+		INSERT INTO security_events (id, type, user_id, email, ip, locked_until, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(query, event.ID, event.Type, event.UserID, event.Email, event.IP, event.LockedUntil, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create security event: %w", err)
+	}
+	return nil
+}
+
+// FindAll returns a page of events matching the filter, newest first.
+func (r *securityEventRepository) FindAll(filter domain.SecurityEventFilter) ([]domain.SecurityEvent, error) {
+	b := &queryBuilder{}
+	applySecurityEventFilter(b, filter)
+
+	query := "SELECT id, type, user_id, email, ip, locked_until, created_at FROM security_events" + b.whereClause() +
+		" ORDER BY created_at DESC, id LIMIT " + b.arg(filter.Limit) + " OFFSET " + b.arg(filter.Offset)
+
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find security events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.SecurityEvent{}
+	for rows.Next() {
+		var e domain.SecurityEvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.UserID, &e.Email, &e.IP, &e.LockedUntil, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan security event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// Count returns the number of events matching the filter, ignoring paging.
+func (r *securityEventRepository) Count(filter domain.SecurityEventFilter) (int, error) {
+	b := &queryBuilder{}
+	applySecurityEventFilter(b, filter)
+
+	var count int
+	query := "SELECT COUNT(*) FROM security_events" + b.whereClause()
+	if err := r.db.QueryRow(query, b.args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count security events: %w", err)
+	}
+	return count, nil
+}
+
+func applySecurityEventFilter(b *queryBuilder, filter domain.SecurityEventFilter) {
+	if filter.Type != nil {
+		b.where("type = " + b.arg(*filter.Type))
+	}
+	if filter.UserID != "" {
+		b.where("user_id = " + b.arg(filter.UserID))
+	}
+	if filter.Email != "" {
+		b.where("LOWER(email) = LOWER(" + b.arg(filter.Email) + ")")
+	}
+	if filter.IP != "" {
+		b.where("ip = " + b.arg(filter.IP))
+	}
+	if filter.Since != nil {
+		b.where("created_at >= " + b.arg(*filter.Since))
+	}
+}
@@ -7,38 +7,250 @@ import (
 	"time"
 
 	"task-management-api/internal/domain"
+
+	"github.com/lib/pq"
 )
 
+// TaskRepository reads and writes tasks. A repository returned by ForOrg only
+// sees the tasks of that organization; the unscoped one is meant for the
+// background worker, which acts across organizations.
 type TaskRepository interface {
+	ForOrg(orgID string) TaskRepository
 	Create(task *domain.Task) error
 	FindByID(id string) (*domain.Task, error)
 	FindAll(filter domain.TaskFilter, userID string, isAdmin bool) ([]domain.Task, error)
+	Count(filter domain.TaskFilter, userID string, isAdmin bool) (int, error)
 	Update(task *domain.Task) error
 	Delete(id string) error
-	FindPendingTasksOlderThan(duration time.Duration) ([]domain.Task, error)
-	UpdateStatus(id string, status domain.TaskStatus) error
+	FindAutoCompleteDue(defaultDelay time.Duration, subtasksBlock bool) ([]domain.Task, error)
+	FindRemindersDue() ([]domain.Task, error)
+	FindOverdueUnmarked() ([]domain.Task, error)
+	UpdateStatus(id string, status domain.TaskStatus, category domain.StatusCategory) error
+	MarkReminded(id string, at time.Time) error
+	MarkOverdue(id string, at time.Time) error
+	FindChildren(parentID string) ([]domain.Task, error)
+	FindDescendants(id string) ([]domain.Task, error)
+	FindAncestorIDs(id string) ([]string, error)
+	FindSubtaskProgress(ids []string) (map[string]domain.SubtaskProgress, error)
+	DetachOpenChildren(id string) error
+	DeleteWithDescendants(id string) error
+	FindBlocked(ids []string) (map[string]bool, error)
 }
 
 type taskRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	orgID string
 }
 
 func NewTaskRepository(db *sql.DB) TaskRepository {
 	return &taskRepository{db: db}
 }
 
+func (r *taskRepository) ForOrg(orgID string) TaskRepository {
+	return &taskRepository{db: r.db, orgID: orgID}
+}
+
+// inOrg returns the condition restricting a query to the repository's
+// organization, using the next placeholder after args, together with the
+// extended args. Unscoped repositories add no condition.
+func (r *taskRepository) inOrg(args ...interface{}) (string, []interface{}) {
+	if r.orgID == "" {
+		return "", args
+	}
+	args = append(args, r.orgID)
+	return fmt.Sprintf(" AND org_id = $%d", len(args)), args
+}
+
+// taskColumns lists the columns read by scanTask, in scan order.
+const taskColumns = `id, org_id, user_id, parent_id, workflow_id, title, description, status, status_category, priority,
+	auto_complete_mode, auto_complete_after_minutes, auto_complete_at,
+	due_at, remind_at, reminded_at, overdue_at,
+	created_at, updated_at`
+
+// taskSortColumns maps the sortable task fields to the SQL they sort by. Only
+// whitelisted fields reach the query, so the ORDER BY is never built from
+// user input directly.
+var taskSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"due_at":     "due_at",
+	"priority":   "priority_rank",
+	"title":      "title",
+	"status":     "status",
+	"rank":       "ts_rank(search_vector, query)",
+}
+
+// searchColumns are selected in addition to taskColumns when searching. They
+// refer to the query source added by applyTaskFilter. The text is
+// HTML-escaped before highlighting, so the <mark> tags are the only markup in
+// the result; the parser keeps the entities intact.
+var searchColumns = `
+	ts_rank(search_vector, query),
+	ts_headline('english', ` + escapeHTML("title") + `, query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>'),
+	ts_headline('english', ` + escapeHTML("description") + `, query, 'MaxFragments=2, MaxWords=20, MinWords=5, StartSel=<mark>, StopSel=</mark>')`
+
+// escapeHTML returns SQL escaping the text column like html.EscapeString.
+func escapeHTML(column string) string {
+	expr := "replace(" + column + ", '&', '&amp;')"
+	for _, r := range []struct{ from, to string }{{"<", "&lt;"}, {">", "&gt;"}, {`"`, "&#34;"}, {"''", "&#39;"}} {
+		expr = "replace(" + expr + ", '" + r.from + "', '" + r.to + "')"
+	}
+	return expr
+}
+
+// taskOrderBy builds the ORDER BY clause for a sort specification, falling
+// back to newest first. The id tie-breaker keeps the order stable between
+// pages.
+func taskOrderBy(keys []domain.SortKey) string {
+	if len(keys) == 0 {
+		keys = domain.DefaultTaskSort
+	}
+
+	var terms []string
+	for _, key := range keys {
+		column, ok := taskSortColumns[key.Field]
+		if !ok {
+			continue
+		}
+		direction := "ASC"
+		if key.Desc {
+			direction = "DESC"
+		}
+		terms = append(terms, column+" "+direction+" NULLS LAST")
+	}
+	terms = append(terms, "id ASC")
+	return strings.Join(terms, ", ")
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask scans the taskColumns of a row, followed by any extra columns.
+func scanTask(row rowScanner, task *domain.Task, extra ...interface{}) error {
+	var (
+		mode         sql.NullString
+		afterMinutes sql.NullInt64
+		at           sql.NullTime
+	)
+	dest := []interface{}{
+		&task.ID,
+		&task.OrgID,
+		&task.UserID,
+		&task.ParentID,
+		&task.WorkflowID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.StatusCategory,
+		&task.Priority,
+		&mode,
+		&afterMinutes,
+		&at,
+		&task.DueAt,
+		&task.RemindAt,
+		&task.RemindedAt,
+		&task.OverdueAt,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	task.AutoComplete = nil
+	if mode.Valid {
+		policy := &domain.AutoCompletePolicy{Mode: domain.AutoCompleteMode(mode.String)}
+		if afterMinutes.Valid {
+			minutes := int(afterMinutes.Int64)
+			policy.AfterMinutes = &minutes
+		}
+		if at.Valid {
+			policy.At = &at.Time
+		}
+		task.AutoComplete = policy
+	}
+	return nil
+}
+
+func scanTasks(rows *sql.Rows) ([]domain.Task, error) {
+	defer rows.Close()
+
+	var tasks []domain.Task
+	for rows.Next() {
+		var task domain.Task
+		if err := scanTask(rows, &task); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// scanSearchTasks scans rows selected with searchColumns.
+func scanSearchTasks(rows *sql.Rows) ([]domain.Task, error) {
+	defer rows.Close()
+
+	var tasks []domain.Task
+	for rows.Next() {
+		var task domain.Task
+		match := &domain.SearchMatch{}
+		if err := scanTask(rows, &task, &match.Rank, &match.Title, &match.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		task.Search = match
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// autoCompleteArgs flattens a policy into its nullable column values.
+func autoCompleteArgs(policy *domain.AutoCompletePolicy) (interface{}, interface{}, interface{}) {
+	if policy == nil {
+		return nil, nil, nil
+	}
+	var afterMinutes, at interface{}
+	if policy.AfterMinutes != nil {
+		afterMinutes = *policy.AfterMinutes
+	}
+	if policy.At != nil {
+		at = *policy.At
+	}
+	return policy.Mode, afterMinutes, at
+}
+
 func (r *taskRepository) Create(task *domain.Task) error {
+	if r.orgID != "" {
+		task.OrgID = r.orgID
+	}
+
 	query := `
-		INSERT INTO tasks (id, user_id, title, description, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO tasks (id, org_id, user_id, parent_id, workflow_id, title, description, status, status_category, priority,
+			auto_complete_mode, auto_complete_after_minutes, auto_complete_at,
+			due_at, remind_at,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
+	mode, afterMinutes, at := autoCompleteArgs(task.AutoComplete)
 	_, err := r.db.Exec(
 		query,
 		task.ID,
+		task.OrgID,
 		task.UserID,
+		task.ParentID,
+		task.WorkflowID,
 		task.Title,
 		task.Description,
 		task.Status,
+		task.StatusCategory,
+		task.Priority,
+		mode,
+		afterMinutes,
+		at,
+		task.DueAt,
+		task.RemindAt,
 		task.CreatedAt,
 		task.UpdatedAt,
 	)
@@ -49,21 +261,10 @@ func (r *taskRepository) Create(task *domain.Task) error {
 }
 
 func (r *taskRepository) FindByID(id string) (*domain.Task, error) {
-	query := `
-		SELECT id, user_id, title, description, status, created_at, updated_at
-		FROM tasks
-		WHERE id = $1
-	`
+	org, args := r.inOrg(id)
+	query := "SELECT " + taskColumns + " FROM tasks WHERE id = $1" + org
 	task := &domain.Task{}
-	err := r.db.QueryRow(query, id).Scan(
-		&task.ID,
-		&task.UserID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	err := scanTask(r.db.QueryRow(query, args...), task)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -74,79 +275,203 @@ func (r *taskRepository) FindByID(id string) (*domain.Task, error) {
 }
 
 func (r *taskRepository) FindAll(filter domain.TaskFilter, userID string, isAdmin bool) ([]domain.Task, error) {
-	var conditions []string
-	var args []interface{}
-	argCount := 1
+	b := &queryBuilder{}
+	from := applyTaskFilter(b, filter, r.orgID, userID, isAdmin)
 
-	if !isAdmin {
-		conditions = append(conditions, fmt.Sprintf("user_id = $%d", argCount))
-		args = append(args, userID)
-		argCount++
+	sort := filter.Sort
+	if len(sort) == 0 {
+		sort = domain.DefaultTaskSort
 	}
-
-	if filter.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argCount))
-		args = append(args, *filter.Status)
-		argCount++
+	if filter.Cursor != nil {
+		b.where(keysetCondition(b, sort, filter.Cursor))
 	}
 
-	query := "SELECT id, user_id, title, description, status, created_at, updated_at FROM tasks"
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	columns := taskColumns
+	if filter.Search != "" {
+		columns += "," + searchColumns
 	}
-	query += " ORDER BY created_at DESC"
+
+	query := "SELECT " + columns + " FROM " + from + b.whereClause()
+	query += " ORDER BY " + taskOrderBy(sort)
 
 	if filter.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argCount)
-		args = append(args, filter.Limit)
-		argCount++
+		query += " LIMIT " + b.arg(filter.Limit)
 	}
 
 	if filter.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argCount)
-		args = append(args, filter.Offset)
+		query += " OFFSET " + b.arg(filter.Offset)
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.Query(query, b.args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find tasks: %w", err)
 	}
-	defer rows.Close()
 
 	var tasks []domain.Task
-	for rows.Next() {
-		var task domain.Task
-		if err := rows.Scan(
-			&task.ID,
-			&task.UserID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
+	if filter.Search != "" {
+		tasks, err = scanSearchTasks(rows)
+	} else {
+		tasks, err = scanTasks(rows)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// Count returns the number of tasks matching the filter, ignoring cursor,
+// limit and offset.
+func (r *taskRepository) Count(filter domain.TaskFilter, userID string, isAdmin bool) (int, error) {
+	b := &queryBuilder{}
+	from := applyTaskFilter(b, filter, r.orgID, userID, isAdmin)
+
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM "+from+b.whereClause(), b.args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	return count, nil
+}
+
+// applyTaskFilter adds the conditions shared by FindAll and Count and returns
+// the FROM source. When searching, the parsed tsquery is joined in as "query"
+// so that conditions, ranking and highlighting can refer to it.
+func applyTaskFilter(b *queryBuilder, filter domain.TaskFilter, orgID, userID string, isAdmin bool) string {
+	from := "tasks"
+	if filter.Search != "" {
+		from += ", to_tsquery('english', " + b.arg(filter.Search) + ") query"
+		b.where("search_vector @@ query")
+	}
+
+	if orgID != "" {
+		b.where("org_id = " + b.arg(orgID))
+	}
+
+	// Members see the tasks they created and the tasks assigned to them;
+	// organization admins see every task of the organization
+	if !isAdmin {
+		me := b.arg(userID)
+		b.where("(user_id = " + me + " OR id IN (SELECT task_id FROM task_assignees WHERE user_id = " + me + "))")
+	}
+
+	if filter.AssigneeID != "" {
+		b.where("id IN (SELECT task_id FROM task_assignees WHERE user_id = " + b.arg(filter.AssigneeID) + ")")
+	}
+
+	if filter.CreatedBy != "" {
+		b.where("user_id = " + b.arg(filter.CreatedBy))
+	}
+
+	if filter.Status != nil {
+		b.where("status = " + b.arg(*filter.Status))
+	}
+
+	if filter.Category != nil {
+		b.where("status_category = " + b.arg(*filter.Category))
+	}
+
+	if filter.Due != nil {
+		from, to := filter.Due.Range(time.Now())
+		if from != nil {
+			b.where("due_at >= " + b.arg(*from))
+		}
+		b.where("due_at < " + b.arg(to))
+
+		if *filter.Due == domain.DueOverdue {
+			b.where("status_category <> " + b.arg(domain.CategoryClosed))
 		}
-		tasks = append(tasks, task)
 	}
 
-	return tasks, nil
+	if filter.Blocked != nil {
+		blocked := "EXISTS (" + openBlockersQuery + b.arg(domain.CategoryClosed) + ")"
+		if !*filter.Blocked {
+			blocked = "NOT " + blocked
+		}
+		b.where(blocked)
+	}
+
+	// Labels are personal, so the names refer to the user's own labels
+	if len(filter.Labels) > 0 {
+		labelQuery := `id IN (
+			SELECT tl.task_id FROM task_labels tl
+			JOIN labels l ON l.id = tl.label_id
+			WHERE l.user_id = ` + b.arg(userID) + ` AND l.name = ANY(` + b.arg(pq.Array(filter.Labels)) + `)`
+
+		// "all" requires every requested label to be attached
+		if filter.LabelMatch == domain.LabelMatchAll {
+			labelQuery += " GROUP BY tl.task_id HAVING COUNT(DISTINCT l.name) = " + b.arg(len(filter.Labels))
+		}
+		b.where(labelQuery + ")")
+	}
+
+	return from
+}
+
+// keysetCondition selects the rows that come after the cursor in the given
+// sort order. It expands to
+//
+//	k1 after v1 OR (k1 = v1 AND k2 after v2) OR ... OR (all equal AND id > last id)
+//
+// honouring each key's direction and the NULLS LAST ordering of taskOrderBy.
+func keysetCondition(b *queryBuilder, sort []domain.SortKey, cursor *domain.TaskCursor) string {
+	var alternatives []string
+	var equal []string
+
+	for i, key := range sort {
+		column := taskSortColumns[key.Field]
+		value := cursor.Values[i]
+
+		// Nothing sorts after NULL except further NULLs, which are handled
+		// by the equality prefix of the following keys.
+		if value != nil {
+			op := ">"
+			if key.Desc {
+				op = "<"
+			}
+			placeholder := b.arg(*value)
+			after := fmt.Sprintf("(%s %s %s OR %s IS NULL)", column, op, placeholder, column)
+			alternatives = append(alternatives, joinConditions(append(equal, after)))
+			equal = append(equal, fmt.Sprintf("%s = %s", column, placeholder))
+		} else {
+			equal = append(equal, column+" IS NULL")
+		}
+	}
+
+	alternatives = append(alternatives, joinConditions(append(equal, "id > "+b.arg(cursor.ID))))
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+func joinConditions(conditions []string) string {
+	return "(" + strings.Join(conditions, " AND ") + ")"
 }
 
 func (r *taskRepository) Update(task *domain.Task) error {
 	query := `
 		UPDATE tasks
-		SET title = $1, description = $2, status = $3, updated_at = $4
-		WHERE id = $5
+		SET title = $1, description = $2, status = $3, status_category = $4, priority = $5,
+			auto_complete_mode = $6, auto_complete_after_minutes = $7, auto_complete_at = $8,
+			due_at = $9, remind_at = $10, reminded_at = $11, overdue_at = $12,
+			parent_id = $13, updated_at = $14
+		WHERE id = $15
 	`
-	_, err := r.db.Exec(
-		query,
+	mode, afterMinutes, at := autoCompleteArgs(task.AutoComplete)
+	org, args := r.inOrg(
 		task.Title,
 		task.Description,
 		task.Status,
+		task.StatusCategory,
+		task.Priority,
+		mode,
+		afterMinutes,
+		at,
+		task.DueAt,
+		task.RemindAt,
+		task.RemindedAt,
+		task.OverdueAt,
+		task.ParentID,
 		task.UpdatedAt,
 		task.ID,
 	)
+	_, err := r.db.Exec(query+org, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
@@ -154,56 +479,338 @@ func (r *taskRepository) Update(task *domain.Task) error {
 }
 
 func (r *taskRepository) Delete(id string) error {
-	query := "DELETE FROM tasks WHERE id = $1"
-	_, err := r.db.Exec(query, id)
+	org, args := r.inOrg(id)
+	query := "DELETE FROM tasks WHERE id = $1" + org
+	_, err := r.db.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
 	return nil
 }
 
-func (r *taskRepository) FindPendingTasksOlderThan(duration time.Duration) ([]domain.Task, error) {
-	cutoffTime := time.Now().Add(-duration)
+// FindAutoCompleteDue returns open tasks whose auto-completion time has passed
+// according to their policy, or defaultDelay for tasks without one. Tasks with
+// an active or dead-lettered auto-completion job are left to the job queue.
+// Tasks that cannot be completed yet are left out: those with an open
+// blocker and, if subtasksBlock is set, those with an open subtask at any
+// depth.
+func (r *taskRepository) FindAutoCompleteDue(defaultDelay time.Duration, subtasksBlock bool) ([]domain.Task, error) {
+	now := time.Now()
+	openSubtasks := ""
+	if subtasksBlock {
+		openSubtasks = `
+		AND NOT EXISTS (
+			WITH RECURSIVE descendants AS (
+				SELECT id, status_category FROM tasks child WHERE child.parent_id = tasks.id
+				UNION
+				SELECT t.id, t.status_category FROM tasks t JOIN descendants d ON t.parent_id = d.id
+			)
+			SELECT 1 FROM descendants WHERE status_category <> $11
+		)`
+	}
 	query := `
-		SELECT id, user_id, title, description, status, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE (status = $1 OR status = $2) AND created_at < $3
+		WHERE (status_category = $1 OR status_category = $2)
+		AND (
+			(auto_complete_mode IS NULL AND created_at < $3)
+			OR (auto_complete_mode = $4 AND created_at + auto_complete_after_minutes * INTERVAL '1 minute' < $5)
+			OR (auto_complete_mode = $6 AND auto_complete_at < $5)
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM jobs
+			WHERE jobs.task_id = tasks.id AND jobs.type = $7 AND jobs.status IN ($8, $9, $10)
+		)
+		AND NOT EXISTS (` + openBlockersQuery + `$11)` + openSubtasks + `
 	`
-	rows, err := r.db.Query(query, domain.StatusPending, domain.StatusInProgress, cutoffTime)
+	org, args := r.inOrg(
+		domain.CategoryOpen,
+		domain.CategoryActive,
+		now.Add(-defaultDelay),
+		domain.AutoCompleteAfter,
+		now,
+		domain.AutoCompleteAt,
+		domain.JobAutoCompleteTask,
+		domain.JobQueued,
+		domain.JobRunning,
+		domain.JobDead,
+		domain.CategoryClosed,
+	)
+	rows, err := r.db.Query(query+org, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find pending tasks: %w", err)
 	}
+	return tasks, nil
+}
+
+func (r *taskRepository) UpdateStatus(id string, status domain.TaskStatus, category domain.StatusCategory) error {
+	query := `
+		UPDATE tasks
+		SET status = $1, status_category = $2, updated_at = $3
+		WHERE id = $4
+	`
+	org, args := r.inOrg(status, category, time.Now(), id)
+	_, err := r.db.Exec(query+org, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update task status: %w", err)
+	}
+	return nil
+}
+
+// FindRemindersDue returns open tasks whose reminder time has passed without
+// a reminder having been sent or a reminder job being in flight.
+func (r *taskRepository) FindRemindersDue() ([]domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE remind_at <= $1 AND reminded_at IS NULL AND status_category <> $2
+		AND NOT EXISTS (
+			SELECT 1 FROM jobs
+			WHERE jobs.task_id = tasks.id AND jobs.type = $3 AND jobs.status IN ($4, $5, $6)
+		)
+	`
+	org, args := r.inOrg(
+		time.Now(),
+		domain.CategoryClosed,
+		domain.JobTaskReminder,
+		domain.JobQueued,
+		domain.JobRunning,
+		domain.JobDead,
+	)
+	rows, err := r.db.Query(query+org, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due reminders: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due reminders: %w", err)
+	}
+	return tasks, nil
+}
+
+// FindOverdueUnmarked returns open tasks past their due date that have not
+// been flagged as overdue yet.
+func (r *taskRepository) FindOverdueUnmarked() ([]domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE due_at <= $1 AND overdue_at IS NULL AND status_category <> $2
+		AND NOT EXISTS (
+			SELECT 1 FROM jobs
+			WHERE jobs.task_id = tasks.id AND jobs.type = $3 AND jobs.status IN ($4, $5, $6)
+		)
+	`
+	org, args := r.inOrg(
+		time.Now(),
+		domain.CategoryClosed,
+		domain.JobTaskOverdue,
+		domain.JobQueued,
+		domain.JobRunning,
+		domain.JobDead,
+	)
+	rows, err := r.db.Query(query+org, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find overdue tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find overdue tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) MarkReminded(id string, at time.Time) error {
+	org, args := r.inOrg(at, id)
+	query := "UPDATE tasks SET reminded_at = $1 WHERE id = $2" + org
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to mark task reminded: %w", err)
+	}
+	return nil
+}
+
+func (r *taskRepository) MarkOverdue(id string, at time.Time) error {
+	org, args := r.inOrg(at, id)
+	query := "UPDATE tasks SET overdue_at = $1 WHERE id = $2" + org
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to mark task overdue: %w", err)
+	}
+	return nil
+}
+
+// openBlockersQuery selects the open tasks blocking tasks.id. It ends with the
+// comparison against the closed category, whose placeholder is appended.
+const openBlockersQuery = `
+	SELECT 1 FROM task_dependencies d
+	JOIN tasks blocker ON blocker.id = d.blocked_by_id
+	WHERE d.task_id = tasks.id AND blocker.status_category <> `
+
+// FindBlocked reports for each of the given tasks whether it is blocked by
+// at least one task that is not completed. Unblocked tasks are absent from
+// the result.
+func (r *taskRepository) FindBlocked(ids []string) (map[string]bool, error) {
+	blocked := make(map[string]bool)
+	if len(ids) == 0 {
+		return blocked, nil
+	}
+
+	org, args := r.inOrg(pq.Array(ids), domain.CategoryClosed)
+	query := `
+		SELECT id FROM tasks
+		WHERE id = ANY($1) AND EXISTS (` + openBlockersQuery + `$2)` + org
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blocked tasks: %w", err)
+	}
 	defer rows.Close()
 
-	var tasks []domain.Task
 	for rows.Next() {
-		var task domain.Task
-		if err := rows.Scan(
-			&task.ID,
-			&task.UserID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked task: %w", err)
 		}
-		tasks = append(tasks, task)
+		blocked[id] = true
+	}
+	return blocked, rows.Err()
+}
+
+// descendantsCTE selects the IDs of all tasks below $1. UNION rather than
+// UNION ALL makes the recursion terminate even if the data contains a cycle.
+const descendantsCTE = `
+	WITH RECURSIVE descendants AS (
+		SELECT id FROM tasks WHERE parent_id = $1
+		UNION
+		SELECT t.id FROM tasks t JOIN descendants d ON t.parent_id = d.id
+	)`
+
+func (r *taskRepository) FindChildren(parentID string) ([]domain.Task, error) {
+	org, args := r.inOrg(parentID)
+	query := "SELECT " + taskColumns + " FROM tasks WHERE parent_id = $1" + org + " ORDER BY created_at, id"
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subtasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subtasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// FindDescendants returns every task below id, at any depth.
+func (r *taskRepository) FindDescendants(id string) ([]domain.Task, error) {
+	org, args := r.inOrg(id)
+	query := descendantsCTE + `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id IN (SELECT id FROM descendants)` + org + `
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subtasks: %w", err)
 	}
 
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subtasks: %w", err)
+	}
 	return tasks, nil
 }
 
-func (r *taskRepository) UpdateStatus(id string, status domain.TaskStatus) error {
+// FindAncestorIDs returns id and the IDs of all tasks above it.
+func (r *taskRepository) FindAncestorIDs(id string) ([]string, error) {
+	org, args := r.inOrg(id)
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM tasks WHERE id = $1` + org + `
+			UNION
+			SELECT t.id, t.parent_id FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+		)
+		SELECT id FROM ancestors
+	`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find parent tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var ancestorID string
+		if err := rows.Scan(&ancestorID); err != nil {
+			return nil, fmt.Errorf("failed to scan parent task: %w", err)
+		}
+		ids = append(ids, ancestorID)
+	}
+	return ids, rows.Err()
+}
+
+// FindSubtaskProgress counts the direct subtasks of many tasks in a single
+// query. Tasks without subtasks are absent from the result.
+func (r *taskRepository) FindSubtaskProgress(ids []string) (map[string]domain.SubtaskProgress, error) {
+	progress := make(map[string]domain.SubtaskProgress)
+	if len(ids) == 0 {
+		return progress, nil
+	}
+
+	org, args := r.inOrg(pq.Array(ids), domain.CategoryClosed)
+	query := `
+		SELECT parent_id, COUNT(*), COUNT(*) FILTER (WHERE status_category = $2)
+		FROM tasks
+		WHERE parent_id = ANY($1)` + org + `
+		GROUP BY parent_id
+	`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subtask progress: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID string
+		var p domain.SubtaskProgress
+		if err := rows.Scan(&parentID, &p.Total, &p.Completed); err != nil {
+			return nil, fmt.Errorf("failed to scan subtask progress: %w", err)
+		}
+		progress[parentID] = p
+	}
+	return progress, rows.Err()
+}
+
+// DetachOpenChildren moves the direct subtasks of id that are not completed
+// to the top level.
+func (r *taskRepository) DetachOpenChildren(id string) error {
 	query := `
 		UPDATE tasks
-		SET status = $1, updated_at = $2
-		WHERE id = $3
+		SET parent_id = NULL, updated_at = $3
+		WHERE parent_id = $1 AND status_category <> $2
 	`
-	_, err := r.db.Exec(query, status, time.Now(), id)
+	org, args := r.inOrg(id, domain.CategoryClosed, time.Now())
+	_, err := r.db.Exec(query+org, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update task status: %w", err)
+		return fmt.Errorf("failed to detach subtasks: %w", err)
+	}
+	return nil
+}
+
+// DeleteWithDescendants deletes id and every task below it.
+func (r *taskRepository) DeleteWithDescendants(id string) error {
+	query := descendantsCTE + `
+		DELETE FROM tasks
+		WHERE (id = $1 OR id IN (SELECT id FROM descendants))
+	`
+	org, args := r.inOrg(id)
+	_, err := r.db.Exec(query+org, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"task-management-api/internal/domain"
+)
+
+type TokenRepository interface {
+	CreateRefreshToken(token *domain.RefreshToken) error
+	FindRefreshTokenByHash(hash string) (*domain.RefreshToken, error)
+	RotateRefreshToken(id, replacedBy string) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID string) error
+	RevokeOtherSessions(userID, keepFamilyID string) error
+	RevokeAccessToken(token *domain.RevokedToken) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type tokenRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRepository(db *sql.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) CreateRefreshToken(token *domain.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, org_id, family_id, token_hash, access_jti, access_expires_at, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.db.Exec(
+		query,
+		token.ID,
+		token.UserID,
+		token.OrgID,
+		token.FamilyID,
+		token.TokenHash,
+		token.AccessJTI,
+		token.AccessExpiresAt,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) FindRefreshTokenByHash(hash string) (*domain.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, org_id, family_id, token_hash, access_jti, access_expires_at, expires_at, revoked_at, replaced_by, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+	token := &domain.RefreshToken{}
+	err := r.db.QueryRow(query, hash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.OrgID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.AccessJTI,
+		&token.AccessExpiresAt,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+		&token.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// RotateRefreshToken marks the token as used and replaced by another one. It
+// reports false when the token had already been revoked, which means a
+// concurrent or replayed refresh got there first.
+func (r *tokenRepository) RotateRefreshToken(id, replacedBy string) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $1, replaced_by = $2
+		WHERE id = $3 AND revoked_at IS NULL
+	`
+	result, err := r.db.Exec(query, time.Now(), replacedBy, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// RevokeFamily revokes every refresh token in the family together with the
+// access tokens that were issued alongside them. Access tokens are recorded
+// until they expire, and only while they have not.
+func (r *tokenRepository) RevokeFamily(familyID string) error {
+	query := `
+		WITH family AS (
+			UPDATE refresh_tokens
+			SET revoked_at = COALESCE(revoked_at, $2)
+			WHERE family_id = $1 AND expires_at > $2
+			RETURNING user_id, access_jti, access_expires_at
+		)
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		SELECT access_jti, user_id, access_expires_at, $2 FROM family WHERE access_expires_at > $2
+		ON CONFLICT (jti) DO NOTHING
+	`
+	if _, err := r.db.Exec(query, familyID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) RevokeAllForUser(userID string) error {
+	query := `
+		WITH sessions AS (
+			UPDATE refresh_tokens
+			SET revoked_at = COALESCE(revoked_at, $2)
+			WHERE user_id = $1 AND expires_at > $2
+			RETURNING user_id, access_jti, access_expires_at
+		)
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		SELECT access_jti, user_id, access_expires_at, $2 FROM sessions WHERE access_expires_at > $2
+		ON CONFLICT (jti) DO NOTHING
+	`
+	if _, err := r.db.Exec(query, userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+// RevokeOtherSessions revokes every session of the user except one.
+func (r *tokenRepository) RevokeOtherSessions(userID, keepFamilyID string) error {
+	query := `
+		WITH sessions AS (
+			UPDATE refresh_tokens
+			SET revoked_at = COALESCE(revoked_at, $2)
+			WHERE user_id = $1 AND family_id <> $3 AND expires_at > $2
+			RETURNING user_id, access_jti, access_expires_at
+		)
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		SELECT access_jti, user_id, access_expires_at, $2 FROM sessions WHERE access_expires_at > $2
+		ON CONFLICT (jti) DO NOTHING
+	`
+	if _, err := r.db.Exec(query, userID, time.Now(), keepFamilyID); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) RevokeAccessToken(token *domain.RevokedToken) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	_, err := r.db.Exec(query, token.JTI, token.UserID, token.ExpiresAt, token.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	query := "SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)"
+	var revoked bool
+	if err := r.db.QueryRow(query, jti).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return revoked, nil
+}
+
+// DeleteExpired removes refresh tokens and revocation records that have
+// expired, since expired tokens are refused anyway. It returns the number of
+// rows removed.
+func (r *tokenRepository) DeleteExpired(now time.Time) (int64, error) {
+	var deleted int64
+	for _, query := range []string{
+		"DELETE FROM revoked_tokens WHERE expires_at <= $1",
+		"DELETE FROM refresh_tokens WHERE expires_at <= $1",
+	} {
+		result, err := r.db.Exec(query, now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete expired tokens: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete expired tokens: %w", err)
+		}
+		deleted += rows
+	}
+	return deleted, nil
+}
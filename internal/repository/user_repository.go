@@ -3,6 +3,8 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"task-management-api/internal/domain"
 )
@@ -11,6 +13,21 @@ type UserRepository interface {
 	Create(user *domain.User) error
 	FindByEmail(email string) (*domain.User, error)
 	FindByID(id string) (*domain.User, error)
+	FindAll(filter domain.UserFilter) ([]domain.User, error)
+	Count(filter domain.UserFilter) (int, error)
+	CountTasks(id string) (*domain.UserTaskCounts, error)
+	FindByRole(role domain.UserRole) ([]domain.User, error)
+	UpdateRole(id string, role domain.UserRole) error
+	Demote(id string) (bool, error)
+	CountByRole(role domain.UserRole) (int, error)
+	SetDisabled(id string, disabledAt *time.Time) error
+	SetPasswordResetRequired(id string, required bool) error
+	UpdatePassword(id, hash string) error
+	UpdateProfile(user *domain.User) error
+	UpdateEmail(id, email string) error
+	MarkEmailVerified(id string, at time.Time) error
+	Delete(id string, reassignTo *string) error
+	Anonymize(id, email string, at time.Time) error
 }
 
 type userRepository struct {
@@ -21,17 +38,44 @@ func NewUserRepository(db *sql.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
+const userColumns = `id, email, email_verified_at, password, role, display_name, timezone, locale, avatar_url,
+	disabled_at, password_reset_required, deleted_at, created_at, updated_at,
+	EXISTS (SELECT 1 FROM user_mfa WHERE user_id = users.id AND confirmed_at IS NOT NULL)`
+
+func scanUser(row rowScanner, user *domain.User) error {
+	return row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.Password,
+		&user.Role,
+		&user.DisplayName,
+		&user.Timezone,
+		&user.Locale,
+		&user.AvatarURL,
+		&user.DisabledAt,
+		&user.PasswordResetRequired,
+		&user.DeletedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.MFAEnabled,
+	)
+}
+
 func (r *userRepository) Create(user *domain.User) error {
 	query := `
-		INSERT INTO users (id, email, password, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, email, email_verified_at, password, role, timezone, locale, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 	_, err := r.db.Exec(
 		query,
 		user.ID,
 		user.Email,
+		user.EmailVerifiedAt,
 		user.Password,
 		user.Role,
+		user.Timezone,
+		user.Locale,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
@@ -42,20 +86,9 @@ func (r *userRepository) Create(user *domain.User) error {
 }
 
 func (r *userRepository) FindByEmail(email string) (*domain.User, error) {
-	query := `
-		SELECT id, email, password, role, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
+	query := "SELECT " + userColumns + " FROM users WHERE email = $1"
 	user := &domain.User{}
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	err := scanUser(r.db.QueryRow(query, email), user)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -66,20 +99,9 @@ func (r *userRepository) FindByEmail(email string) (*domain.User, error) {
 }
 
 func (r *userRepository) FindByID(id string) (*domain.User, error) {
-	query := `
-		SELECT id, email, password, role, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
+	query := "SELECT " + userColumns + " FROM users WHERE id = $1"
 	user := &domain.User{}
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	err := scanUser(r.db.QueryRow(query, id), user)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -88,3 +110,360 @@ func (r *userRepository) FindByID(id string) (*domain.User, error) {
 	}
 	return user, nil
 }
+
+// FindAll returns a page of users matching the filter, oldest first.
+func (r *userRepository) FindAll(filter domain.UserFilter) ([]domain.User, error) {
+	b := &queryBuilder{}
+	applyUserFilter(b, filter)
+
+	query := "SELECT " + userColumns + " FROM users" + b.whereClause() +
+		" ORDER BY created_at, id LIMIT " + b.arg(filter.Limit) + " OFFSET " + b.arg(filter.Offset)
+
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		var user domain.User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// Count returns the number of users matching the filter, ignoring paging.
+func (r *userRepository) Count(filter domain.UserFilter) (int, error) {
+	b := &queryBuilder{}
+	applyUserFilter(b, filter)
+
+	var count int
+	query := "SELECT COUNT(*) FROM users" + b.whereClause()
+	if err := r.db.QueryRow(query, b.args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+func applyUserFilter(b *queryBuilder, filter domain.UserFilter) {
+	if filter.Query != "" {
+		// Escape LIKE wildcards so the query matches literally
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(filter.Query)
+		b.where("email ILIKE " + b.arg("%"+escaped+"%"))
+	}
+	if filter.Role != nil {
+		b.where("role = " + b.arg(*filter.Role))
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			b.where("disabled_at IS NOT NULL")
+		} else {
+			b.where("disabled_at IS NULL")
+		}
+	}
+}
+
+// CountTasks counts the user's tasks across all organizations.
+func (r *userRepository) CountTasks(id string) (*domain.UserTaskCounts, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM tasks WHERE user_id = $1),
+			COUNT(t.id),
+			COUNT(t.id) FILTER (WHERE t.status_category <> $2),
+			COUNT(t.id) FILTER (WHERE t.status_category <> $2 AND t.overdue_at IS NOT NULL)
+		FROM task_assignees a
+		JOIN tasks t ON t.id = a.task_id
+		WHERE a.user_id = $1
+	`
+	counts := &domain.UserTaskCounts{}
+	err := r.db.QueryRow(query, id, domain.CategoryClosed).Scan(&counts.Created, &counts.Assigned, &counts.Open, &counts.Overdue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *userRepository) FindByRole(role domain.UserRole) ([]domain.User, error) {
+	query := "SELECT " + userColumns + " FROM users WHERE role = $1 ORDER BY created_at, id"
+	rows, err := r.db.Query(query, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		var user domain.User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *userRepository) UpdateRole(id string, role domain.UserRole) error {
+	query := "UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2"
+	if _, err := r.db.Exec(query, role, id); err != nil {
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+	return nil
+}
+
+// Demote turns an admin into a normal user unless they are the last admin.
+// It reports whether the user was demoted; the check and the update are one
+// statement so that two admins cannot demote each other at the same time.
+func (r *userRepository) Demote(id string) (bool, error) {
+	query := `
+		UPDATE users SET role = $1, updated_at = NOW()
+		WHERE id = $2 AND role = $3
+		  AND (SELECT COUNT(*) FROM users WHERE role = $3) > 1
+	`
+	result, err := r.db.Exec(query, domain.RoleUser, id, domain.RoleAdmin)
+	if err != nil {
+		return false, fmt.Errorf("failed to demote user: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to demote user: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *userRepository) CountByRole(role domain.UserRole) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM users WHERE role = $1"
+	if err := r.db.QueryRow(query, role).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+// SetDisabled disables the user at the given time, or enables them for nil.
+func (r *userRepository) SetDisabled(id string, disabledAt *time.Time) error {
+	query := "UPDATE users SET disabled_at = $1, updated_at = NOW() WHERE id = $2"
+	if _, err := r.db.Exec(query, disabledAt, id); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) SetPasswordResetRequired(id string, required bool) error {
+	query := "UPDATE users SET password_reset_required = $1, updated_at = NOW() WHERE id = $2"
+	if _, err := r.db.Exec(query, required, id); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+// UpdatePassword stores a new password hash, which also satisfies a pending
+// password reset requirement.
+func (r *userRepository) UpdatePassword(id, hash string) error {
+	query := "UPDATE users SET password = $1, password_reset_required = FALSE, updated_at = NOW() WHERE id = $2"
+	if _, err := r.db.Exec(query, hash, id); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) UpdateProfile(user *domain.User) error {
+	query := `
+		UPDATE users
+		SET display_name = $1, timezone = $2, locale = $3, avatar_url = $4, updated_at = $5
+		WHERE id = $6
+	`
+	_, err := r.db.Exec(query, user.DisplayName, user.Timezone, user.Locale, user.AvatarURL, user.UpdatedAt, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+	return nil
+}
+
+// UpdateEmail changes the email address. The new address counts as verified,
+// as changes are confirmed through it.
+func (r *userRepository) UpdateEmail(id, email string) error {
+	query := "UPDATE users SET email = $1, email_verified_at = NOW(), updated_at = NOW() WHERE id = $2"
+	if _, err := r.db.Exec(query, email, id); err != nil {
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) MarkEmailVerified(id string, at time.Time) error {
+	query := "UPDATE users SET email_verified_at = $1, updated_at = $1 WHERE id = $2"
+	if _, err := r.db.Exec(query, at, id); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+	return nil
+}
+
+// sqlStep is one statement of a multi-statement transaction.
+type sqlStep struct {
+	name  string
+	query string
+	args  []interface{}
+}
+
+// Delete removes a user in one transaction. The tasks the user created go to
+// reassignTo, which also takes over the user's assignments and the workflows
+// still used by remaining tasks; without it they are deleted. reassignTo
+// first joins the organizations of those tasks as a member, so the tasks
+// never leave their organization's members and organizations holding them
+// survive. Organizations in which the user is then the only member are
+// deleted with their tasks; where the user is the last owner, the
+// longest-standing other member becomes owner.
+func (r *userRepository) Delete(id string, reassignTo *string) error {
+	var steps []sqlStep
+	if reassignTo != nil {
+		steps = append(steps, sqlStep{"add heir to organizations", `
+			INSERT INTO org_memberships (org_id, user_id, role, created_at)
+			SELECT DISTINCT org_id, $2, $3, NOW() FROM tasks
+			WHERE user_id = $1 OR id IN (SELECT task_id FROM task_assignees WHERE user_id = $1)
+			ON CONFLICT DO NOTHING
+		`, []interface{}{id, *reassignTo, domain.OrgRoleMember}})
+	}
+	steps = append(steps, leaveOrgSteps(id)...)
+
+	if reassignTo != nil {
+		steps = append(steps,
+			sqlStep{"reassign tasks", `
+				UPDATE tasks SET user_id = $2, updated_at = NOW() WHERE user_id = $1
+			`, []interface{}{id, *reassignTo}},
+			sqlStep{"reassign assignments", `
+				INSERT INTO task_assignees (task_id, user_id, assigned_by, assigned_at)
+				SELECT task_id, $2, NULL, NOW() FROM task_assignees WHERE user_id = $1
+				ON CONFLICT DO NOTHING
+			`, []interface{}{id, *reassignTo}},
+			// Workflow names are unique per user; a clashing name gets the
+			// previous owner's email appended
+			sqlStep{"reassign workflows", `
+				UPDATE workflows w SET user_id = $2, updated_at = NOW(),
+					name = CASE
+						WHEN EXISTS (SELECT 1 FROM workflows WHERE user_id = $2 AND name = w.name)
+						THEN LEFT(w.name || ' (' || (SELECT email FROM users WHERE id = $1) || ')', 100)
+						ELSE w.name
+					END
+				WHERE w.user_id = $1 AND EXISTS (SELECT 1 FROM tasks WHERE workflow_id = w.id)
+			`, []interface{}{id, *reassignTo}},
+		)
+	} else {
+		steps = append(steps,
+			sqlStep{"delete tasks", `
+				DELETE FROM tasks WHERE user_id = $1
+			`, []interface{}{id}},
+			// Tasks of others fall back to the default workflow, keeping
+			// their status category
+			sqlStep{"detach workflows", `
+				UPDATE tasks SET workflow_id = NULL, updated_at = NOW(),
+					status = CASE status_category WHEN $2 THEN $3 WHEN $4 THEN $5 ELSE $6 END
+				WHERE workflow_id IN (SELECT id FROM workflows WHERE user_id = $1)
+			`, []interface{}{id,
+				domain.CategoryClosed, domain.StatusCompleted,
+				domain.CategoryActive, domain.StatusInProgress,
+				domain.StatusPending}},
+		)
+	}
+
+	steps = append(steps, sqlStep{"delete user", `
+		DELETE FROM users WHERE id = $1
+	`, []interface{}{id}})
+
+	return r.run(steps)
+}
+
+// Anonymize removes the personal data of a user but keeps the account row,
+// so the tasks they created stay with their organizations. The user leaves
+// all organizations like in Delete, loses their assignments and labels, and
+// can no longer log in.
+func (r *userRepository) Anonymize(id, email string, at time.Time) error {
+	steps := append(leaveOrgSteps(id),
+		sqlStep{"remove memberships", `
+			DELETE FROM org_memberships WHERE user_id = $1
+		`, []interface{}{id}},
+		sqlStep{"remove assignments", `
+			DELETE FROM task_assignees WHERE user_id = $1
+		`, []interface{}{id}},
+		sqlStep{"delete labels", `
+			DELETE FROM labels WHERE user_id = $1
+		`, []interface{}{id}},
+		sqlStep{"delete email changes", `
+			DELETE FROM email_changes WHERE user_id = $1
+		`, []interface{}{id}},
+		sqlStep{"delete email verifications", `
+			DELETE FROM email_verifications WHERE user_id = $1
+		`, []interface{}{id}},
+		sqlStep{"delete second factor", `
+			DELETE FROM user_mfa WHERE user_id = $1
+		`, []interface{}{id}},
+		sqlStep{"delete recovery codes", `
+			DELETE FROM mfa_recovery_codes WHERE user_id = $1
+		`, []interface{}{id}},
+		sqlStep{"delete login challenges", `
+			DELETE FROM mfa_challenges WHERE user_id = $1
+		`, []interface{}{id}},
+		sqlStep{"delete access tokens", `
+			DELETE FROM personal_access_tokens WHERE user_id = $1
+		`, []interface{}{id}},
+		// An empty hash matches no password
+		sqlStep{"anonymize user", `
+			UPDATE users
+			SET email = $2, email_verified_at = NULL, password = '', role = $3, display_name = NULL, avatar_url = NULL,
+				password_reset_required = FALSE, disabled_at = $4, deleted_at = $4, updated_at = $4
+			WHERE id = $1
+		`, []interface{}{id, email, domain.RoleUser, at}},
+	)
+
+	return r.run(steps)
+}
+
+// leaveOrgSteps removes the organizations in which the user is the only
+// member and, where the user is the last owner, makes the longest-standing
+// other member owner.
+func leaveOrgSteps(id string) []sqlStep {
+	return []sqlStep{
+		{"delete organizations", `
+			DELETE FROM organizations o
+			WHERE EXISTS (SELECT 1 FROM org_memberships WHERE org_id = o.id AND user_id = $1)
+			  AND NOT EXISTS (SELECT 1 FROM org_memberships WHERE org_id = o.id AND user_id <> $1)
+		`, []interface{}{id}},
+		{"hand over organizations", `
+			UPDATE org_memberships m SET role = $2
+			FROM (
+				SELECT DISTINCT ON (org_id) org_id, user_id
+				FROM org_memberships
+				WHERE user_id <> $1 AND org_id IN (
+					SELECT org_id FROM org_memberships WHERE user_id = $1 AND role = $2
+				)
+				ORDER BY org_id, created_at, user_id
+			) heir
+			WHERE m.org_id = heir.org_id AND m.user_id = heir.user_id
+			  AND NOT EXISTS (
+				SELECT 1 FROM org_memberships
+				WHERE org_id = m.org_id AND role = $2 AND user_id <> $1
+			  )
+		`, []interface{}{id, domain.OrgRoleOwner}},
+	}
+}
+
+// run executes the steps in one transaction.
+func (r *userRepository) run(steps []sqlStep) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, step := range steps {
+		if _, err := tx.Exec(step.query, step.args...); err != nil {
+			return fmt.Errorf("failed to %s: %w", step.name, err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"task-management-api/internal/domain"
+)
+
+type WorkflowRepository interface {
+	Create(workflow *domain.Workflow) error
+	FindByID(id string) (*domain.Workflow, error)
+	FindByName(userID, name string) (*domain.Workflow, error)
+	FindByUser(userID string) ([]domain.Workflow, error)
+	FindByOrg(orgID string) ([]domain.Workflow, error)
+	Delete(id string) error
+	IsInUse(id string) (bool, error)
+}
+
+type workflowRepository struct {
+	db *sql.DB
+}
+
+func NewWorkflowRepository(db *sql.DB) WorkflowRepository {
+	return &workflowRepository{db: db}
+}
+
+const workflowColumns = `id, user_id, name, initial_status, statuses, transitions, created_at, updated_at`
+
+// scanWorkflow scans workflowColumns, decoding the JSONB definition.
+func scanWorkflow(row rowScanner, workflow *domain.Workflow) error {
+	var statuses, transitions []byte
+	if err := row.Scan(
+		&workflow.ID,
+		&workflow.UserID,
+		&workflow.Name,
+		&workflow.InitialStatus,
+		&statuses,
+		&transitions,
+		&workflow.CreatedAt,
+		&workflow.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(statuses, &workflow.Statuses); err != nil {
+		return fmt.Errorf("failed to decode workflow statuses: %w", err)
+	}
+	if err := json.Unmarshal(transitions, &workflow.Transitions); err != nil {
+		return fmt.Errorf("failed to decode workflow transitions: %w", err)
+	}
+	return nil
+}
+
+func (r *workflowRepository) Create(workflow *domain.Workflow) error {
+	statuses, err := json.Marshal(workflow.Statuses)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow statuses: %w", err)
+	}
+	transitions, err := json.Marshal(workflow.Transitions)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow transitions: %w", err)
+	}
+
+	query := `
+		INSERT INTO workflows (id, user_id, name, initial_status, statuses, transitions, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = r.db.Exec(
+		query,
+		workflow.ID,
+		workflow.UserID,
+		workflow.Name,
+		workflow.InitialStatus,
+		statuses,
+		transitions,
+		workflow.CreatedAt,
+		workflow.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create workflow: %w", err)
+	}
+	return nil
+}
+
+func (r *workflowRepository) FindByID(id string) (*domain.Workflow, error) {
+	query := "SELECT " + workflowColumns + " FROM workflows WHERE id = $1"
+	workflow := &domain.Workflow{}
+	err := scanWorkflow(r.db.QueryRow(query, id), workflow)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+func (r *workflowRepository) FindByName(userID, name string) (*domain.Workflow, error) {
+	query := "SELECT " + workflowColumns + " FROM workflows WHERE user_id = $1 AND name = $2"
+	workflow := &domain.Workflow{}
+	err := scanWorkflow(r.db.QueryRow(query, userID, name), workflow)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+func (r *workflowRepository) FindByUser(userID string) ([]domain.Workflow, error) {
+	query := "SELECT " + workflowColumns + " FROM workflows WHERE user_id = $1 ORDER BY name"
+	return r.findAll(query, userID)
+}
+
+// FindByOrg returns the workflows of all members of the organization.
+func (r *workflowRepository) FindByOrg(orgID string) ([]domain.Workflow, error) {
+	query := `
+		SELECT ` + workflowColumns + `
+		FROM workflows
+		WHERE user_id IN (SELECT user_id FROM org_memberships WHERE org_id = $1)
+		ORDER BY name, created_at
+	`
+	return r.findAll(query, orgID)
+}
+
+func (r *workflowRepository) findAll(query string, args ...interface{}) ([]domain.Workflow, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find workflows: %w", err)
+	}
+	defer rows.Close()
+
+	workflows := []domain.Workflow{}
+	for rows.Next() {
+		var workflow domain.Workflow
+		if err := scanWorkflow(rows, &workflow); err != nil {
+			return nil, fmt.Errorf("failed to scan workflow: %w", err)
+		}
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, rows.Err()
+}
+
+func (r *workflowRepository) Delete(id string) error {
+	query := "DELETE FROM workflows WHERE id = $1"
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete workflow: %w", err)
+	}
+	return nil
+}
+
+// IsInUse reports whether any task still follows the workflow.
+func (r *workflowRepository) IsInUse(id string) (bool, error) {
+	var inUse bool
+	query := "SELECT EXISTS (SELECT 1 FROM tasks WHERE workflow_id = $1)"
+	if err := r.db.QueryRow(query, id).Scan(&inUse); err != nil {
+		return false, fmt.Errorf("failed to check workflow usage: %w", err)
+	}
+	return inUse, nil
+}
@@ -3,6 +3,8 @@ package routes
 import (
 	"time"
 
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
 	"task-management-api/internal/handler"
 	"task-management-api/internal/middleware"
 	"task-management-api/internal/service"
@@ -15,25 +17,132 @@ func SetupRoutes(
 	app *fiber.App,
 	authHandler *handler.AuthHandler,
 	taskHandler *handler.TaskHandler,
+	labelHandler *handler.LabelHandler,
+	dependencyHandler *handler.DependencyHandler,
+	workflowHandler *handler.WorkflowHandler,
+	orgHandler *handler.OrgHandler,
+	permissionHandler *handler.PermissionHandler,
+	adminHandler *handler.AdminHandler,
+	userHandler *handler.UserHandler,
+	profileHandler *handler.ProfileHandler,
+	passwordResetHandler *handler.PasswordResetHandler,
+	verificationHandler *handler.EmailVerificationHandler,
+	mfaHandler *handler.MFAHandler,
+	securityHandler *handler.SecurityHandler,
+	accessTokenHandler *handler.AccessTokenHandler,
 	authService service.AuthService,
+	workerService service.WorkerService,
+	cfg *config.Config,
 ) {
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
-			"status": "ok",
-			"time":   time.Now(),
+			"status":         "ok",
+			"time":           time.Now(),
+			"scheduled_jobs": workerService.ScheduledJobs(),
 		})
 	})
 
 	// Auth routes (public)
 	app.Post("/auth/register", authHandler.Register)
 	app.Post("/auth/login", authHandler.Login)
+	app.Post("/auth/mfa/verify", authHandler.VerifyMFA)
+	app.Post("/auth/refresh", authHandler.Refresh)
+	app.Post("/auth/confirm-email", profileHandler.ConfirmEmailChange)
+	app.Post("/auth/verify", verificationHandler.Verify)
+	app.Post("/auth/verify/resend", middleware.RateLimit(cfg.Verify.MaxPerIP, cfg.Verify.Window), verificationHandler.Resend)
+
+	// Password reset (public, limited per client IP)
+	app.Post("/auth/password/forgot", middleware.RateLimit(cfg.Reset.MaxPerIP, cfg.Reset.Window), passwordResetHandler.Forgot)
+	app.Post("/auth/password/reset", middleware.RateLimit(cfg.Reset.MaxPerIP, cfg.Reset.Window), passwordResetHandler.Reset)
+
+	// Session routes (protected)
+	app.Post("/auth/logout", middleware.AuthMiddleware(authService), authHandler.Logout)
+	app.Post("/auth/logout-all", middleware.AuthMiddleware(authService), authHandler.LogoutAll)
+	app.Post("/auth/switch-org", middleware.AuthMiddleware(authService), authHandler.SwitchOrg)
+	app.Get("/auth/permissions", middleware.AuthMiddleware(authService), permissionHandler.Get)
+	app.Post("/auth/accept-invitation", middleware.AuthMiddleware(authService), adminHandler.AcceptInvitation)
+
+	// Own account (protected)
+	me := app.Group("/me", middleware.AuthMiddleware(authService))
+	me.Get("/", profileHandler.Get)
+	me.Patch("/", profileHandler.Update)
+	me.Delete("/", profileHandler.Delete)
+	me.Post("/password", profileHandler.ChangePassword)
+	me.Post("/email", profileHandler.RequestEmailChange)
+	me.Get("/mfa", mfaHandler.Status)
+	me.Post("/mfa", mfaHandler.Enroll)
+	me.Post("/mfa/confirm", mfaHandler.Confirm)
+	me.Delete("/mfa", mfaHandler.Disable)
+	me.Post("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+	me.Get("/tokens", accessTokenHandler.List)
+	me.Post("/tokens", accessTokenHandler.Create)
+	me.Delete("/tokens/:id", accessTokenHandler.Revoke)
 
 	// Task routes (protected)
-	api := app.Group("/tasks", middleware.AuthMiddleware(authService))
-	api.Post("/", taskHandler.Create)
+	// Tasks belong to the session's organization; what the user may do with
+	// them is decided by the policy. Personal access tokens are accepted with
+	// the tasks scopes
+	api := app.Group("/tasks",
+		middleware.TokenScopeMiddleware(domain.ScopeTasksRead, domain.ScopeTasksWrite),
+		middleware.AuthMiddleware(authService),
+		middleware.OrgMiddleware(),
+	)
+	api.Post("/", middleware.VerifiedEmailMiddleware(cfg.Verify.Policy), taskHandler.Create)
 	api.Get("/", taskHandler.List)
 	api.Get("/:id", taskHandler.GetByID)
 	api.Put("/:id", taskHandler.Update)
 	api.Delete("/:id", taskHandler.Delete)
+	api.Get("/:id/children", taskHandler.Children)
+	api.Get("/:id/tree", taskHandler.Tree)
+	api.Put("/:id/assignees", taskHandler.Reassign)
+	api.Get("/:id/assignees/history", taskHandler.AssignmentHistory)
+	api.Get("/:id/dependencies", dependencyHandler.List)
+	api.Post("/:id/dependencies/:blockerId", dependencyHandler.Add)
+	api.Delete("/:id/dependencies/:blockerId", dependencyHandler.Remove)
+	api.Post("/:id/labels/:labelId", labelHandler.Attach)
+	api.Delete("/:id/labels/:labelId", labelHandler.Detach)
+
+	// Label routes (protected)
+	labels := app.Group("/labels", middleware.AuthMiddleware(authService))
+	labels.Post("/", labelHandler.Create)
+	labels.Get("/", labelHandler.List)
+	labels.Put("/:id", labelHandler.Update)
+	labels.Delete("/:id", labelHandler.Delete)
+
+	// Workflow routes (protected)
+	workflows := app.Group("/workflows", middleware.AuthMiddleware(authService))
+	workflows.Post("/", workflowHandler.Create)
+	workflows.Get("/", workflowHandler.List)
+	workflows.Get("/:id", workflowHandler.GetByID)
+	workflows.Delete("/:id", workflowHandler.Delete)
+
+	// Organization routes (protected)
+	orgs := app.Group("/orgs", middleware.AuthMiddleware(authService))
+	orgs.Post("/", orgHandler.Create)
+	orgs.Get("/", orgHandler.List)
+	orgs.Get("/:id", orgHandler.GetByID)
+	orgs.Delete("/:id", orgHandler.Delete)
+	orgs.Get("/:id/members", orgHandler.Members)
+	orgs.Post("/:id/members", orgHandler.AddMember)
+	orgs.Put("/:id/members/:userId", orgHandler.UpdateMember)
+	orgs.Delete("/:id/members/:userId", orgHandler.RemoveMember)
+
+	// Platform administration (admins only)
+	admin := app.Group("/admin", middleware.AuthMiddleware(authService), middleware.AdminMiddleware())
+	admin.Get("/admins", adminHandler.ListAdmins)
+	admin.Put("/admins/:userId", adminHandler.Promote)
+	admin.Delete("/admins/:userId", adminHandler.Demote)
+	admin.Post("/invitations", adminHandler.Invite)
+	admin.Get("/invitations", adminHandler.Invitations)
+	admin.Delete("/invitations/:id", adminHandler.RevokeInvitation)
+	admin.Get("/users", userHandler.List)
+	admin.Get("/users/:id", userHandler.GetByID)
+	admin.Put("/users/:id/role", userHandler.UpdateRole)
+	admin.Post("/users/:id/disable", userHandler.Disable)
+	admin.Post("/users/:id/enable", userHandler.Enable)
+	admin.Post("/users/:id/password-reset", userHandler.RequirePasswordReset)
+	admin.Delete("/users/:id/mfa", userHandler.ResetMFA)
+	admin.Delete("/users/:id", userHandler.Delete)
+	admin.Get("/security-events", securityHandler.Events)
 }
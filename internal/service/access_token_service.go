@@ -0,0 +1,159 @@
+package service
+
+import (
+	"time"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// accessTokenPrefixLength is how much of a token is kept in clear, enough
+// for users to recognise it in their scripts.
+const accessTokenPrefixLength = 12
+
+// AccessTokenService manages personal access tokens, the long-lived
+// credentials of scripts and CI jobs.
+type AccessTokenService interface {
+	Create(claims *Claims, req domain.CreateAccessTokenRequest) (*domain.PersonalAccessToken, error)
+	List(userID string) ([]domain.PersonalAccessToken, error)
+	Revoke(userID, id string) error
+	RevokeAll(userID string) error
+	Authenticate(token string) (*Claims, error)
+}
+
+type accessTokenService struct {
+	tokenRepo repository.AccessTokenRepository
+	userRepo  repository.UserRepository
+	orgRepo   repository.OrgRepository
+}
+
+func NewAccessTokenService(tokenRepo repository.AccessTokenRepository, userRepo repository.UserRepository, orgRepo repository.OrgRepository) AccessTokenService {
+	return &accessTokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		orgRepo:   orgRepo,
+	}
+}
+
+// Create issues a token for the organization the session works in. The
+// returned token carries the secret, which is not stored and cannot be
+// retrieved again.
+func (s *accessTokenService) Create(claims *Claims, req domain.CreateAccessTokenRequest) (*domain.PersonalAccessToken, error) {
+	if len(req.Scopes) == 0 {
+		return nil, domain.ErrTokenScopesRequired
+	}
+	for _, scope := range req.Scopes {
+		if !scope.IsValid() {
+			return nil, domain.ErrInvalidTokenScope
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, domain.ErrInvalidTokenExpiry
+	}
+	if claims.OrgID == "" {
+		return nil, domain.ErrNoOrganization
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	secret = domain.PersonalAccessTokenPrefix + secret
+
+	orgID := claims.OrgID
+	token := &domain.PersonalAccessToken{
+		ID:        uuid.New().String(),
+		UserID:    claims.UserID,
+		OrgID:     &orgID,
+		Name:      req.Name,
+		Token:     secret,
+		Prefix:    secret[:accessTokenPrefixLength],
+		TokenHash: hashToken(secret),
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (s *accessTokenService) List(userID string) ([]domain.PersonalAccessToken, error) {
+	return s.tokenRepo.FindByUser(userID)
+}
+
+// Revoke ends a token of the user. Tokens of other users are reported as
+// not found.
+func (s *accessTokenService) Revoke(userID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.ErrAccessTokenNotFound
+	}
+	token, err := s.tokenRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if token == nil || token.UserID != userID || token.RevokedAt != nil {
+		return domain.ErrAccessTokenNotFound
+	}
+	return s.tokenRepo.Revoke(id, time.Now())
+}
+
+// RevokeAll ends every token of the user. It is part of ending all sessions,
+// so that a password reset after a suspected compromise also locks out
+// scripts using a stolen token.
+func (s *accessTokenService) RevokeAll(userID string) error {
+	return s.tokenRepo.RevokeAllForUser(userID, time.Now())
+}
+
+// Authenticate turns a personal access token into the claims of its user,
+// with the same checks as a session: disabled users are refused, and a user
+// removed from the token's organization loses access to its data.
+func (s *accessTokenService) Authenticate(secret string) (*Claims, error) {
+	token, err := s.tokenRepo.FindByHash(hashToken(secret))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if token == nil || token.RevokedAt != nil || (token.ExpiresAt != nil && now.After(*token.ExpiresAt)) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidToken
+	}
+	if user.DisabledAt != nil {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	claims := &Claims{
+		UserID:        user.ID,
+		Email:         user.Email,
+		Role:          string(user.Role),
+		EmailVerified: user.EmailVerifiedAt != nil,
+		AccessToken:   token,
+	}
+	if token.OrgID != nil {
+		member, err := s.orgRepo.FindMembership(*token.OrgID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if member != nil {
+			claims.OrgID = member.OrgID
+			claims.OrgRole = member.Role
+		}
+	}
+
+	if err := s.tokenRepo.Touch(token.ID, now); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
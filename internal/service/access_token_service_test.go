@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+)
+
+type fakeAccessTokenRepo struct {
+	repository.AccessTokenRepository
+	tokens []*domain.PersonalAccessToken
+}
+
+func (r *fakeAccessTokenRepo) FindByHash(hash string) (*domain.PersonalAccessToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAccessTokenRepo) RevokeAllForUser(userID string, at time.Time) error {
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *fakeAccessTokenRepo) Touch(id string, at time.Time) error {
+	return nil
+}
+
+// fakeSessionRepo accepts revoking sessions; only access tokens are
+// checked here.
+type fakeSessionRepo struct {
+	repository.TokenRepository
+}
+
+func (r *fakeSessionRepo) RevokeAllForUser(userID string) error {
+	return nil
+}
+
+type fakeResetRepo struct {
+	repository.PasswordResetRepository
+	userID string
+}
+
+func (r *fakeResetRepo) Consume(hash string, at time.Time) (*domain.PasswordReset, error) {
+	return &domain.PasswordReset{UserID: r.userID}, nil
+}
+
+func (r *fakeResetRepo) InvalidateForUser(userID string, at time.Time) error {
+	return nil
+}
+
+func (r *fakeUserRepo) UpdatePassword(id, hash string) error {
+	return nil
+}
+
+func (r *fakeUserRepo) SetPasswordResetRequired(id string, required bool) error {
+	return nil
+}
+
+func TestEndingAllSessionsRevokesAccessTokens(t *testing.T) {
+	const userID = "6f1c7a52-3c1e-4d8e-9a43-1f0c2b5d7e90"
+
+	tests := []struct {
+		name   string
+		revoke func(users repository.UserRepository, tokens AccessTokenService) error
+	}{
+		{
+			name: "log out everywhere",
+			revoke: func(users repository.UserRepository, tokens AccessTokenService) error {
+				auth := NewAuthService(users, &fakeSessionRepo{}, nil, nil, nil, nil, tokens, &config.Config{})
+				return auth.LogoutAll(userID)
+			},
+		},
+		{
+			name: "password reset",
+			revoke: func(users repository.UserRepository, tokens AccessTokenService) error {
+				resets := NewPasswordResetService(users, &fakeSessionRepo{}, &fakeResetRepo{userID: userID}, tokens, nil, &config.Config{})
+				return resets.Reset(domain.ResetPasswordRequest{Token: "reset-token", NewPassword: "new-password456"})
+			},
+		},
+		{
+			name: "admin requires a new password",
+			revoke: func(users repository.UserRepository, tokens AccessTokenService) error {
+				_, err := NewUserService(users, &fakeSessionRepo{}, nil, nil, tokens).RequirePasswordReset(userID)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const secret = domain.PersonalAccessTokenPrefix + "secret"
+			users := &fakeUserRepo{user: &domain.User{ID: userID, Email: "user@example.com"}}
+			repo := &fakeAccessTokenRepo{tokens: []*domain.PersonalAccessToken{
+				{ID: "token-1", UserID: userID, TokenHash: hashToken(secret)},
+			}}
+			tokens := NewAccessTokenService(repo, users, nil)
+
+			if _, err := tokens.Authenticate(secret); err != nil {
+				t.Fatalf("Authenticate() before error = %v", err)
+			}
+			if err := tt.revoke(users, tokens); err != nil {
+				t.Fatalf("revoke error = %v", err)
+			}
+			if _, err := tokens.Authenticate(secret); !errors.Is(err, domain.ErrInvalidToken) {
+				t.Errorf("Authenticate() after error = %v, want %v", err, domain.ErrInvalidToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Errors of Bootstrap, which runs outside of requests.
+var (
+	ErrAdminExists           = errors.New("an admin already exists")
+	ErrAdminPasswordRequired = errors.New("a password is required to create the admin account")
+)
+
+// AdminService manages platform admins. Apart from Bootstrap and
+// AcceptInvitation, its methods are meant for admins only.
+type AdminService interface {
+	Bootstrap(email, password string) (*domain.User, error)
+	ListAdmins() ([]domain.User, error)
+	Promote(userID string) (*domain.User, error)
+	Demote(userID string) (*domain.User, error)
+	Invite(req domain.InviteAdminRequest, invitedBy string) (*domain.AdminInvitation, error)
+	Invitations() ([]domain.AdminInvitation, error)
+	RevokeInvitation(id string) error
+	AcceptInvitation(req domain.AcceptInvitationRequest, userID string) (*domain.User, error)
+}
+
+type adminService struct {
+	userRepo       repository.UserRepository
+	orgRepo        repository.OrgRepository
+	invitationRepo repository.InvitationRepository
+	config         *config.Config
+}
+
+func NewAdminService(userRepo repository.UserRepository, orgRepo repository.OrgRepository, invitationRepo repository.InvitationRepository, cfg *config.Config) AdminService {
+	return &adminService{
+		userRepo:       userRepo,
+		orgRepo:        orgRepo,
+		invitationRepo: invitationRepo,
+		config:         cfg,
+	}
+}
+
+// Bootstrap makes the user with the email the first admin, creating the
+// account with the password if it does not exist. It does nothing but return
+// ErrAdminExists once there is an admin, so it is safe to run on every start.
+// Callers validate the format of the email and password.
+func (s *adminService) Bootstrap(email, password string) (*domain.User, error) {
+	admins, err := s.userRepo.CountByRole(domain.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if admins > 0 {
+		return nil, ErrAdminExists
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		if password == "" {
+			return nil, ErrAdminPasswordRequired
+		}
+		return createUser(s.userRepo, s.orgRepo, email, password, domain.RoleAdmin, true)
+	}
+
+	if err := s.userRepo.UpdateRole(user.ID, domain.RoleAdmin); err != nil {
+		return nil, err
+	}
+	user.Role = domain.RoleAdmin
+	return user, nil
+}
+
+func (s *adminService) ListAdmins() ([]domain.User, error) {
+	return s.userRepo.FindByRole(domain.RoleAdmin)
+}
+
+func (s *adminService) Promote(userID string) (*domain.User, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == domain.RoleAdmin {
+		return user, nil
+	}
+
+	if err := s.userRepo.UpdateRole(user.ID, domain.RoleAdmin); err != nil {
+		return nil, err
+	}
+	user.Role = domain.RoleAdmin
+	return user, nil
+}
+
+// Demote turns an admin back into a normal user. The last admin cannot be
+// demoted, not even by themselves.
+func (s *adminService) Demote(userID string) (*domain.User, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != domain.RoleAdmin {
+		return user, nil
+	}
+
+	demoted, err := s.userRepo.Demote(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !demoted {
+		return nil, domain.ErrLastAdmin
+	}
+	user.Role = domain.RoleUser
+	return user, nil
+}
+
+// Invite creates an invitation for the email. The returned invitation carries
+// the token, which is not stored and cannot be retrieved again.
+func (s *adminService) Invite(req domain.InviteAdminRequest, invitedBy string) (*domain.AdminInvitation, error) {
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil && user.Role == domain.RoleAdmin {
+		return nil, domain.ErrAlreadyAdmin
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &domain.AdminInvitation{
+		ID:        uuid.New().String(),
+		Email:     req.Email,
+		Token:     token,
+		TokenHash: hashToken(token),
+		InvitedBy: &invitedBy,
+		ExpiresAt: time.Now().Add(s.config.Admin.InvitationExpiry),
+		CreatedAt: time.Now(),
+	}
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (s *adminService) Invitations() ([]domain.AdminInvitation, error) {
+	return s.invitationRepo.FindPending()
+}
+
+func (s *adminService) RevokeInvitation(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.ErrInvitationNotFound
+	}
+	invitation, err := s.invitationRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if invitation == nil {
+		return domain.ErrInvitationNotFound
+	}
+	return s.invitationRepo.Delete(id)
+}
+
+// AcceptInvitation makes the user an admin if the token belongs to a pending
+// invitation for their email address. Each invitation can be used once.
+func (s *adminService) AcceptInvitation(req domain.AcceptInvitationRequest, userID string) (*domain.User, error) {
+	invitation, err := s.invitationRepo.FindByHash(hashToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil || invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		return nil, domain.ErrInvalidInvitation
+	}
+
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, domain.ErrInvalidInvitation
+	}
+
+	accepted, err := s.invitationRepo.Accept(invitation.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, domain.ErrInvalidInvitation
+	}
+
+	return s.Promote(user.ID)
+}
+
+func (s *adminService) findUser(id string) (*domain.User, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
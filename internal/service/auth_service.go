@@ -1,7 +1,13 @@
 package service
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"task-management-api/internal/config"
@@ -13,31 +19,67 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Claims are carried by access tokens. The registered ID claim (jti) names
+// the individual token and SessionID ties it to its refresh token family.
+// OrgID is the organization the session works in; the user's role there is
+// looked up on every request so that membership changes apply immediately,
+// as is whether the email address is verified.
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID        string         `json:"user_id"`
+	Email         string         `json:"email"`
+	Role          string         `json:"role"`
+	SessionID     string         `json:"sid"`
+	OrgID         string         `json:"org_id,omitempty"`
+	OrgRole       domain.OrgRole `json:"-"`
+	EmailVerified bool           `json:"-"`
+	// AccessToken is set when the request authenticated with a personal
+	// access token instead of a session
+	AccessToken *domain.PersonalAccessToken `json:"-"`
 	jwt.RegisteredClaims
 }
 
 type AuthService interface {
 	Register(req domain.RegisterRequest) (*domain.User, error)
-	Login(req domain.LoginRequest) (*domain.LoginResponse, error)
+	Login(req domain.LoginRequest, ip string) (*domain.LoginResponse, *domain.MFAChallengeResponse, error)
+	VerifyMFA(req domain.VerifyMFARequest, ip string) (*domain.LoginResponse, error)
+	Refresh(req domain.RefreshRequest) (*domain.LoginResponse, error)
+	Logout(claims *Claims) error
+	SwitchOrg(claims *Claims, orgID string) (*domain.LoginResponse, error)
+	LogoutAll(userID string) error
 	ValidateToken(tokenString string) (*Claims, error)
 }
 
 type authService struct {
-	userRepo repository.UserRepository
-	config   *config.Config
+	userRepo            repository.UserRepository
+	tokenRepo           repository.TokenRepository
+	orgRepo             repository.OrgRepository
+	verificationService EmailVerificationService
+	mfaService          MFAService
+	loginGuard          LoginGuard
+	accessTokenService  AccessTokenService
+	config              *config.Config
 }
 
-func NewAuthService(userRepo repository.UserRepository, cfg *config.Config) AuthService {
+// dummyHash is checked against for unknown emails, so that a login takes as
+// long whether or not the account exists.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("no account has this password"), bcrypt.DefaultCost)
+
+func NewAuthService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, orgRepo repository.OrgRepository, verificationService EmailVerificationService, mfaService MFAService, loginGuard LoginGuard, accessTokenService AccessTokenService, cfg *config.Config) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		config:   cfg,
+		userRepo:            userRepo,
+		tokenRepo:           tokenRepo,
+		orgRepo:             orgRepo,
+		verificationService: verificationService,
+		mfaService:          mfaService,
+		loginGuard:          loginGuard,
+		accessTokenService:  accessTokenService,
+		config:              cfg,
 	}
 }
 
+// Register creates a normal user and mails them a token to verify their
+// email address. Admins are only made through the bootstrap or by other
+// admins.
 func (s *authService) Register(req domain.RegisterRequest) (*domain.User, error) {
 	// Check if user exists
 	existingUser, err := s.userRepo.FindByEmail(req.Email)
@@ -45,64 +87,309 @@ func (s *authService) Register(req domain.RegisterRequest) (*domain.User, error)
 		return nil, err
 	}
 	if existingUser != nil {
-		return nil, fmt.Errorf("user already exists")
+		return nil, domain.ErrUserExists
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	user, err := createUser(s.userRepo, s.orgRepo, req.Email, req.Password, domain.RoleUser, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %w", err)
+		return nil, err
 	}
 
-	// Set role
-	role := domain.RoleUser
-	if req.Role == string(domain.RoleAdmin) {
-		role = domain.RoleAdmin
+	if err := s.verificationService.Send(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createUser stores a new user with the given role. Every user starts out
+// owning a personal organization. Verified marks the email address verified
+// right away, for accounts set up by operators.
+func createUser(userRepo repository.UserRepository, orgRepo repository.OrgRepository, email, password string, role domain.UserRole, verified bool) (*domain.User, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	user := &domain.User{
 		ID:        uuid.New().String(),
-		Email:     req.Email,
+		Email:     email,
 		Password:  string(hashedPassword),
 		Role:      role,
+		Timezone:  domain.DefaultTimezone,
+		Locale:    domain.DefaultLocale,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	if verified {
+		user.EmailVerifiedAt = &user.CreatedAt
+	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	org := &domain.Organization{
+		ID:        uuid.New().String(),
+		Name:      domain.PersonalOrgName,
+		CreatedBy: &user.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	owner := &domain.Membership{OrgID: org.ID, UserID: user.ID, Role: domain.OrgRoleOwner, CreatedAt: time.Now()}
+	if err := orgRepo.Create(org, owner); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (s *authService) Login(req domain.LoginRequest) (*domain.LoginResponse, error) {
+// Login checks the password of a login from the client IP, unless the login
+// guard refuses the attempt. Users with a second factor get a challenge
+// instead of tokens, which VerifyMFA completes; only then are the failures
+// counted for the account cleared.
+func (s *authService) Login(req domain.LoginRequest, ip string) (*domain.LoginResponse, *domain.MFAChallengeResponse, error) {
+	if err := s.loginGuard.Check(req.Email, ip); err != nil {
+		return nil, nil, err
+	}
+
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if user == nil {
-		return nil, fmt.Errorf("invalid credentials")
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(req.Password))
+		return nil, nil, s.loginFailed(req.Email, ip, nil)
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		return nil, nil, s.loginFailed(req.Email, ip, &user.ID)
+	}
+	if user.DisabledAt != nil {
+		return nil, nil, domain.ErrAccountDisabled
+	}
+	if user.EmailVerifiedAt == nil && s.config.Verify.Policy == domain.VerifyBeforeLogin {
+		return nil, nil, domain.ErrEmailNotVerified
+	}
+
+	// An admin may require a new password, which is then set as part of
+	// logging in. With a second factor it is only stored once VerifyMFA has
+	// checked it.
+	var newPasswordHash *string
+	if user.PasswordResetRequired {
+		if req.NewPassword == "" {
+			return nil, nil, domain.ErrPasswordResetRequired
+		}
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		hash := string(hashedPassword)
+		newPasswordHash = &hash
 	}
 
-	// Generate token
-	token, err := s.generateToken(user)
+	challenge, err := s.mfaService.StartChallenge(user.ID, newPasswordHash)
+	if err != nil || challenge != nil {
+		return nil, challenge, err
+	}
+
+	if err := s.loginGuard.Success(req.Email); err != nil {
+		return nil, nil, err
+	}
+	if err := s.applyNewPassword(user, newPasswordHash); err != nil {
+		return nil, nil, err
+	}
+
+	response, err := s.startSession(user)
+	return response, nil, err
+}
+
+// loginFailed counts the failed login and returns the error to report.
+func (s *authService) loginFailed(email, ip string, userID *string) error {
+	if err := s.loginGuard.Failure(email, ip, userID); err != nil {
+		return err
+	}
+	return domain.ErrInvalidCredentials
+}
+
+// VerifyMFA completes a login from the client IP with the second factor.
+// Wrong codes count as failed logins of the account, whichever challenge
+// they are tried with, so the login guard locks out code guessing too.
+func (s *authService) VerifyMFA(req domain.VerifyMFARequest, ip string) (*domain.LoginResponse, error) {
+	challenge, err := s.mfaService.FindChallenge(req.ChallengeToken)
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.LoginResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	user, err := s.userRepo.FindByID(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+	if err := s.loginGuard.Check(user.Email, ip); err != nil {
+		return nil, err
+	}
+
+	err = s.mfaService.CompleteChallenge(challenge, req)
+	if errors.Is(err, domain.ErrInvalidMFACode) {
+		if err := s.loginGuard.Failure(user.Email, ip, &user.ID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidMFACode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DisabledAt != nil {
+		return nil, domain.ErrAccountDisabled
+	}
+	if err := s.loginGuard.Success(user.Email); err != nil {
+		return nil, err
+	}
+	if err := s.applyNewPassword(user, challenge.NewPasswordHash); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user)
+}
+
+// applyNewPassword stores the password a login had to set, if any.
+func (s *authService) applyNewPassword(user *domain.User, hash *string) error {
+	if hash == nil {
+		return nil
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, *hash); err != nil {
+		return err
+	}
+	user.Password = *hash
+	user.PasswordResetRequired = false
+	return nil
+}
+
+// startSession issues the tokens of a new session in the user's default
+// organization.
+func (s *authService) startSession(user *domain.User) (*domain.LoginResponse, error) {
+	orgID, err := s.sessionOrg(user.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, orgID, uuid.New().String(), uuid.New().String())
+}
+
+func (s *authService) Refresh(req domain.RefreshRequest) (*domain.LoginResponse, error) {
+	current, err := s.tokenRepo.FindRefreshTokenByHash(hashToken(req.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	// A revoked token being presented again means it was stolen or replayed,
+	// so the whole session is killed.
+	if current.RevokedAt != nil {
+		if err := s.tokenRepo.RevokeFamily(current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	nextID := uuid.New().String()
+	rotated, err := s.tokenRepo.RotateRefreshToken(current.ID, nextID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		if err := s.tokenRepo.RevokeFamily(current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	user, err := s.userRepo.FindByID(current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if user.DisabledAt != nil {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	// Stay in the session's organization unless the user has left it
+	orgID, err := s.sessionOrg(user.ID, current.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, orgID, current.FamilyID, nextID)
+}
+
+func (s *authService) Logout(claims *Claims) error {
+	if err := s.tokenRepo.RevokeFamily(claims.SessionID); err != nil {
+		return err
+	}
+
+	// The family covers tokens issued through refresh; revoke the presented
+	// token explicitly as well.
+	return s.tokenRepo.RevokeAccessToken(&domain.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt.Time,
+		RevokedAt: time.Now(),
+	})
+}
+
+// SwitchOrg ends the current session and starts a new one in another
+// organization the user is a member of.
+func (s *authService) SwitchOrg(claims *Claims, orgID string) (*domain.LoginResponse, error) {
+	if _, err := uuid.Parse(orgID); err != nil {
+		return nil, domain.ErrOrgNotFound
+	}
+	member, err := s.orgRepo.FindMembership(orgID, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, domain.ErrOrgNotFound
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if err := s.Logout(claims); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(user, orgID, uuid.New().String(), uuid.New().String())
+}
+
+// LogoutAll ends every session and personal access token of the user.
+func (s *authService) LogoutAll(userID string) error {
+	if err := s.tokenRepo.RevokeAllForUser(userID); err != nil {
+		return err
+	}
+	return s.accessTokenService.RevokeAll(userID)
 }
 
 func (s *authService) ValidateToken(tokenString string) (*Claims, error) {
+	if strings.HasPrefix(tokenString, domain.PersonalAccessTokenPrefix) {
+		return s.accessTokenService.Authenticate(tokenString)
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -110,24 +397,129 @@ func (s *authService) ValidateToken(tokenString string) (*Claims, error) {
 		return []byte(s.config.JWT.Secret), nil
 	})
 
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid || claims.ID == "" {
+		return nil, domain.ErrInvalidToken
+	}
+
+	revoked, err := s.tokenRepo.IsAccessTokenRevoked(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, domain.ErrTokenRevoked
+	}
+
+	// The platform role and email are looked up as well, so that promotions,
+	// demotions and email changes apply to existing sessions
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidToken
+	}
+	if user.DisabledAt != nil {
+		return nil, domain.ErrAccountDisabled
+	}
+	claims.Role = string(user.Role)
+	claims.Email = user.Email
+	claims.EmailVerified = user.EmailVerifiedAt != nil
+
+	// A user removed from the organization keeps the session but loses
+	// access to its data
+	if claims.OrgID != "" {
+		member, err := s.orgRepo.FindMembership(claims.OrgID, claims.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if member == nil {
+			claims.OrgID = ""
+		} else {
+			claims.OrgRole = member.Role
+		}
+	}
+
+	return claims, nil
+}
+
+// sessionOrg returns the organization a session works in: the requested one
+// while the user is still a member, otherwise the user's oldest membership.
+// It is empty for users without any organization.
+func (s *authService) sessionOrg(userID string, requested *string) (string, error) {
+	if requested != nil {
+		member, err := s.orgRepo.FindMembership(*requested, userID)
+		if err != nil {
+			return "", err
+		}
+		if member != nil {
+			return member.OrgID, nil
+		}
+	}
+
+	member, err := s.orgRepo.FindDefaultMembership(userID)
+	if err != nil || member == nil {
+		return "", err
+	}
+	return member.OrgID, nil
+}
+
+// issueTokens signs a new access token for the organization and stores the
+// refresh token paired with it under the given family.
+func (s *authService) issueTokens(user *domain.User, orgID, familyID, refreshID string) (*domain.LoginResponse, error) {
+	jti := uuid.New().String()
+	expiresAt := time.Now().Add(s.config.JWT.Expiry)
+
+	accessToken, err := s.generateToken(user, orgID, jti, familyID, expiresAt)
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("invalid token")
+	refresh := &domain.RefreshToken{
+		ID:              refreshID,
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       hashToken(refreshToken),
+		AccessJTI:       jti,
+		AccessExpiresAt: expiresAt,
+		ExpiresAt:       time.Now().Add(s.config.JWT.RefreshExpiry),
+		CreatedAt:       time.Now(),
+	}
+	if orgID != "" {
+		refresh.OrgID = &orgID
+	}
+	if err := s.tokenRepo.CreateRefreshToken(refresh); err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+		OrgID:        orgID,
+		User:         *user,
+	}, nil
 }
 
-func (s *authService) generateToken(user *domain.User) (string, error) {
+func (s *authService) generateToken(user *domain.User, orgID, jti, sessionID string, expiresAt time.Time) (string, error) {
 	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   string(user.Role),
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		SessionID: sessionID,
+		OrgID:     orgID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.JWT.Expiry)),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -135,3 +527,19 @@ func (s *authService) generateToken(user *domain.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.config.JWT.Secret))
 }
+
+// randomToken returns an opaque token for refresh tokens and invitations.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the form in which opaque tokens are stored, so a
+// database leak does not expose usable tokens.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
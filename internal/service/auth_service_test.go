@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	user *domain.User
+}
+
+func (r *fakeUserRepo) FindByEmail(email string) (*domain.User, error) {
+	if email != r.user.Email {
+		return nil, nil
+	}
+	return r.user, nil
+}
+
+func (r *fakeUserRepo) FindByID(id string) (*domain.User, error) {
+	if id != r.user.ID {
+		return nil, nil
+	}
+	return r.user, nil
+}
+
+// fakeMFAService hands out a new challenge for every login and accepts no
+// code.
+type fakeMFAService struct {
+	MFAService
+	challenges map[string]*domain.MFAChallenge
+}
+
+func (s *fakeMFAService) StartChallenge(userID string, newPasswordHash *string) (*domain.MFAChallengeResponse, error) {
+	token := fmt.Sprintf("challenge-%d", len(s.challenges))
+	s.challenges[token] = &domain.MFAChallenge{ID: token, UserID: userID, ExpiresAt: time.Now().Add(time.Minute)}
+	return &domain.MFAChallengeResponse{MFARequired: true, ChallengeToken: token}, nil
+}
+
+func (s *fakeMFAService) FindChallenge(token string) (*domain.MFAChallenge, error) {
+	challenge, ok := s.challenges[token]
+	if !ok {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+	return challenge, nil
+}
+
+func (s *fakeMFAService) CompleteChallenge(challenge *domain.MFAChallenge, req domain.VerifyMFARequest) error {
+	return domain.ErrInvalidMFACode
+}
+
+type fakeAttemptRepo struct {
+	attempts map[string]*domain.LoginAttempts
+}
+
+func (r *fakeAttemptRepo) Find(kind domain.AttemptKind, key string) (*domain.LoginAttempts, error) {
+	return r.attempts[string(kind)+":"+key], nil
+}
+
+func (r *fakeAttemptRepo) RecordFailure(kind domain.AttemptKind, key string, at time.Time, window time.Duration) (*domain.LoginAttempts, error) {
+	a, ok := r.attempts[string(kind)+":"+key]
+	if !ok {
+		a = &domain.LoginAttempts{Kind: kind, Key: key, WindowStart: at}
+		r.attempts[string(kind)+":"+key] = a
+	}
+	if a.WindowStart.Before(at.Add(-window)) {
+		a.Failures = 0
+		a.WindowStart = at
+	}
+	a.Failures++
+	a.LastFailureAt = at
+	return a, nil
+}
+
+func (r *fakeAttemptRepo) Lock(kind domain.AttemptKind, key string, at, until time.Time) error {
+	a := r.attempts[string(kind)+":"+key]
+	a.LockedUntil = &until
+	a.Failures = 0
+	a.WindowStart = at
+	return nil
+}
+
+func (r *fakeAttemptRepo) Reset(kind domain.AttemptKind, key string) error {
+	delete(r.attempts, string(kind)+":"+key)
+	return nil
+}
+
+type fakeEventRepo struct {
+	repository.SecurityEventRepository
+	events []domain.SecurityEvent
+}
+
+func (r *fakeEventRepo) Create(event *domain.SecurityEvent) error {
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func TestWrongMFACodesLockAccount(t *testing.T) {
+	const (
+		email    = "user@example.com"
+		password = "password123"
+		ip       = "203.0.113.7"
+	)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verified := time.Now()
+	users := &fakeUserRepo{user: &domain.User{ID: "user-1", Email: email, Password: string(hash), EmailVerifiedAt: &verified}}
+	mfa := &fakeMFAService{challenges: map[string]*domain.MFAChallenge{}}
+	events := &fakeEventRepo{}
+
+	cfg := &config.Config{Login: config.LoginProtectionConfig{
+		Window:               time.Hour,
+		DelayAfter:           100,
+		AccountLockThreshold: 3,
+		IPLockThreshold:      100,
+		LockDuration:         time.Hour,
+	}}
+	guard := NewLoginGuard(&fakeAttemptRepo{attempts: map[string]*domain.LoginAttempts{}}, events, cfg)
+	auth := NewAuthService(users, nil, nil, nil, mfa, guard, nil, cfg)
+
+	// Every wrong code is tried with a fresh challenge, after a login with
+	// the correct password
+	for i := 0; i < cfg.Login.AccountLockThreshold; i++ {
+		_, challenge, err := auth.Login(domain.LoginRequest{Email: email, Password: password}, ip)
+		if err != nil {
+			t.Fatalf("login %d: error = %v", i+1, err)
+		}
+		if challenge == nil {
+			t.Fatalf("login %d: no challenge", i+1)
+		}
+
+		_, err = auth.VerifyMFA(domain.VerifyMFARequest{ChallengeToken: challenge.ChallengeToken, Code: "000000"}, ip)
+		if !errors.Is(err, domain.ErrInvalidMFACode) {
+			t.Fatalf("code %d: error = %v, want %v", i+1, err, domain.ErrInvalidMFACode)
+		}
+	}
+
+	if _, _, err := auth.Login(domain.LoginRequest{Email: email, Password: password}, ip); !errors.Is(err, domain.ErrLoginLocked) {
+		t.Errorf("login after wrong codes: error = %v, want %v", err, domain.ErrLoginLocked)
+	}
+	if _, err := auth.VerifyMFA(domain.VerifyMFARequest{ChallengeToken: "challenge-0", Code: "000000"}, ip); !errors.Is(err, domain.ErrLoginLocked) {
+		t.Errorf("code after lock: error = %v, want %v", err, domain.ErrLoginLocked)
+	}
+	if len(events.events) != 1 || events.events[0].Type != domain.EventAccountLocked {
+		t.Errorf("events = %+v, want one %s", events.events, domain.EventAccountLocked)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type DependencyService interface {
+	List(taskID string, subject policy.Subject) ([]domain.Task, error)
+	Add(taskID, blockerID string, subject policy.Subject) error
+	Remove(taskID, blockerID string, subject policy.Subject) error
+}
+
+type dependencyService struct {
+	dependencyRepo repository.DependencyRepository
+	guard          *taskGuard
+}
+
+func NewDependencyService(dependencyRepo repository.DependencyRepository, taskRepo repository.TaskRepository, assigneeRepo repository.AssigneeRepository, p *policy.Policy) DependencyService {
+	return &dependencyService{
+		dependencyRepo: dependencyRepo,
+		guard:          &taskGuard{taskRepo: taskRepo, assigneeRepo: assigneeRepo, policy: p},
+	}
+}
+
+// List returns the tasks that block the given task and that the subject may
+// read.
+func (s *dependencyService) List(taskID string, subject policy.Subject) ([]domain.Task, error) {
+	if _, err := s.guard.find(taskID, subject, policy.TaskRead); err != nil {
+		return nil, err
+	}
+
+	blockers, err := s.dependencyRepo.FindBlockers(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.guard.readable(blockers, subject)
+}
+
+func (s *dependencyService) Add(taskID, blockerID string, subject policy.Subject) error {
+	task, err := s.guard.find(taskID, subject, policy.TaskUpdate)
+	if err != nil {
+		return err
+	}
+
+	// Dependencies only link tasks of the same organization that the
+	// subject may read
+	blocker, err := s.guard.find(blockerID, subject, policy.TaskRead)
+	if errors.Is(err, domain.ErrTaskNotFound) || errors.Is(err, domain.ErrTaskAccessDenied) {
+		return domain.ErrBlockerNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.dependencyRepo.Add(task.ID, blocker.ID)
+}
+
+func (s *dependencyService) Remove(taskID, blockerID string, subject policy.Subject) error {
+	if _, err := s.guard.find(taskID, subject, policy.TaskUpdate); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(blockerID); err != nil {
+		return domain.ErrBlockerNotFound
+	}
+	return s.dependencyRepo.Remove(taskID, blockerID)
+}
+
+// checkUnblocked returns domain.ErrTaskBlocked while the task waits for tasks
+// that are not completed yet.
+func checkUnblocked(taskRepo repository.TaskRepository, taskID string) error {
+	blocked, err := taskRepo.FindBlocked([]string{taskID})
+	if err != nil {
+		return err
+	}
+	if blocked[taskID] {
+		return domain.ErrTaskBlocked
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+)
+
+// EmailVerificationService confirms that users own the email address they
+// registered with.
+type EmailVerificationService interface {
+	Send(user *domain.User) error
+	Verify(req domain.VerifyEmailRequest) (*domain.User, error)
+	Resend(req domain.ResendVerificationRequest) error
+}
+
+type emailVerificationService struct {
+	userRepo   repository.UserRepository
+	verifyRepo repository.EmailVerificationRepository
+	mailer     Mailer
+	config     *config.Config
+}
+
+func NewEmailVerificationService(userRepo repository.UserRepository, verifyRepo repository.EmailVerificationRepository, mailer Mailer, cfg *config.Config) EmailVerificationService {
+	return &emailVerificationService{
+		userRepo:   userRepo,
+		verifyRepo: verifyRepo,
+		mailer:     mailer,
+		config:     cfg,
+	}
+}
+
+// Send mails a new verification token to the user, replacing a pending one.
+func (s *emailVerificationService) Send(user *domain.User) error {
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	verification := &domain.EmailVerification{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.config.Verify.Expiry),
+		CreatedAt: time.Now(),
+	}
+	if err := s.verifyRepo.Save(verification); err != nil {
+		return err
+	}
+
+	sendInBackground(s.mailer, domain.Email{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Use this token to verify %s as the email address of your account:\n\n%s\n\nThe token expires at %s.",
+			user.Email, token, verification.ExpiresAt.UTC().Format(time.RFC1123)),
+	})
+	return nil
+}
+
+// Verify marks the address the token was sent to verified, provided it is
+// still the user's address.
+func (s *emailVerificationService) Verify(req domain.VerifyEmailRequest) (*domain.User, error) {
+	verification, err := s.verifyRepo.FindByHash(hashToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	if verification == nil || time.Now().After(verification.ExpiresAt) {
+		return nil, domain.ErrInvalidVerification
+	}
+
+	user, err := s.userRepo.FindByID(verification.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Email != verification.Email {
+		return nil, domain.ErrInvalidVerification
+	}
+
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		if err := s.userRepo.MarkEmailVerified(user.ID, now); err != nil {
+			return nil, err
+		}
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.verifyRepo.Delete(user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Resend mails a new token if the email belongs to an enabled, unverified
+// account whose last token is older than the resend interval. Like Forgot, it
+// succeeds either way so as not to reveal which accounts exist.
+func (s *emailVerificationService) Resend(req domain.ResendVerificationRequest) error {
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.DisabledAt != nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	pending, err := s.verifyRepo.FindByUser(user.ID)
+	if err != nil {
+		return err
+	}
+	if pending != nil && time.Since(pending.CreatedAt) < s.config.Verify.ResendInterval {
+		return nil
+	}
+
+	return s.Send(user)
+}
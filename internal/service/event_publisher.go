@@ -0,0 +1,25 @@
+package service
+
+import (
+	"log"
+
+	"task-management-api/internal/domain"
+)
+
+// EventPublisher delivers task events emitted by the worker, e.g. to a
+// message broker or notification service.
+type EventPublisher interface {
+	Publish(event domain.TaskEvent) error
+}
+
+type logEventPublisher struct{}
+
+// NewLogEventPublisher returns a publisher that writes events to the log.
+func NewLogEventPublisher() EventPublisher {
+	return &logEventPublisher{}
+}
+
+func (p *logEventPublisher) Publish(event domain.TaskEvent) error {
+	log.Printf("Event %s: task %s (%q) of user %s", event.Type, event.TaskID, event.Title, event.UserID)
+	return nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"time"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type LabelService interface {
+	Create(req domain.CreateLabelRequest, userID string) (*domain.Label, error)
+	List(userID string) ([]domain.Label, error)
+	Update(id string, req domain.UpdateLabelRequest, subject policy.Subject) (*domain.Label, error)
+	Delete(id string, subject policy.Subject) error
+	AttachToTask(taskID, labelID string, subject policy.Subject) error
+	DetachFromTask(taskID, labelID string, subject policy.Subject) error
+}
+
+type labelService struct {
+	labelRepo repository.LabelRepository
+	policy    *policy.Policy
+	guard     *taskGuard
+}
+
+func NewLabelService(labelRepo repository.LabelRepository, taskRepo repository.TaskRepository, assigneeRepo repository.AssigneeRepository, p *policy.Policy) LabelService {
+	return &labelService{
+		labelRepo: labelRepo,
+		policy:    p,
+		guard:     &taskGuard{taskRepo: taskRepo, assigneeRepo: assigneeRepo, policy: p},
+	}
+}
+
+func (s *labelService) Create(req domain.CreateLabelRequest, userID string) (*domain.Label, error) {
+	existing, err := s.labelRepo.FindByName(userID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrLabelExists
+	}
+
+	label := &domain.Label{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      req.Name,
+		Color:     req.Color,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.labelRepo.Create(label); err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
+
+func (s *labelService) List(userID string) ([]domain.Label, error) {
+	return s.labelRepo.FindByUser(userID)
+}
+
+func (s *labelService) Update(id string, req domain.UpdateLabelRequest, subject policy.Subject) (*domain.Label, error) {
+	label, err := s.findOwned(id, subject, policy.LabelUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil && *req.Name != label.Name {
+		existing, err := s.labelRepo.FindByName(label.UserID, *req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, domain.ErrLabelExists
+		}
+		label.Name = *req.Name
+	}
+	if req.Color != nil {
+		label.Color = *req.Color
+	}
+	label.UpdatedAt = time.Now()
+
+	if err := s.labelRepo.Update(label); err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
+
+func (s *labelService) Delete(id string, subject policy.Subject) error {
+	if _, err := s.findOwned(id, subject, policy.LabelDelete); err != nil {
+		return err
+	}
+	return s.labelRepo.Delete(id)
+}
+
+// AttachToTask attaches one of the subject's own labels to a task they may
+// update. Labels are personal, so these are the only ones they can see.
+func (s *labelService) AttachToTask(taskID, labelID string, subject policy.Subject) error {
+	task, err := s.guard.find(taskID, subject, policy.TaskUpdate)
+	if err != nil {
+		return err
+	}
+
+	if _, err := uuid.Parse(labelID); err != nil {
+		return domain.ErrLabelNotFound
+	}
+	label, err := s.labelRepo.FindByID(labelID)
+	if err != nil {
+		return err
+	}
+	if label == nil || label.UserID != subject.UserID {
+		return domain.ErrLabelNotFound
+	}
+	return s.labelRepo.Attach(task.ID, label.ID)
+}
+
+// DetachFromTask removes a label from a task the subject may update. Besides
+// their own labels, they may remove any label already attached to the task,
+// e.g. one attached by another member.
+func (s *labelService) DetachFromTask(taskID, labelID string, subject policy.Subject) error {
+	task, err := s.guard.find(taskID, subject, policy.TaskUpdate)
+	if err != nil {
+		return err
+	}
+
+	attached, err := s.labelRepo.FindByTaskIDs([]string{task.ID})
+	if err != nil {
+		return err
+	}
+	for _, label := range attached[task.ID] {
+		if label.ID == labelID {
+			return s.labelRepo.Detach(task.ID, label.ID)
+		}
+	}
+
+	if _, err := uuid.Parse(labelID); err != nil {
+		return domain.ErrLabelNotFound
+	}
+	label, err := s.labelRepo.FindByID(labelID)
+	if err != nil {
+		return err
+	}
+	if label == nil || label.UserID != subject.UserID {
+		return domain.ErrLabelNotFound
+	}
+	return nil
+}
+
+// findOwned returns the label if the user may manage it. Labels of other
+// users are reported as not found rather than forbidden.
+func (s *labelService) findOwned(id string, subject policy.Subject, action policy.Action) (*domain.Label, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrLabelNotFound
+	}
+	label, err := s.labelRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if label == nil || !s.policy.Can(subject, action, policy.OwnedResource(label.UserID)) {
+		return nil, domain.ErrLabelNotFound
+	}
+	return label, nil
+}
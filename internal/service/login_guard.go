@@ -0,0 +1,163 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// LoginGuard protects logins against password guessing. It counts failed
+// logins per account email and per client IP, slows down and then locks
+// accounts, locks IPs, and records lockouts for admins. Emails without an
+// account are treated like any other, so the responses do not tell which
+// accounts exist.
+type LoginGuard interface {
+	Check(email, ip string) error
+	Failure(email, ip string, userID *string) error
+	Success(email string) error
+	Events(filter domain.SecurityEventFilter) (*domain.SecurityEventPage, error)
+}
+
+type loginGuard struct {
+	attemptRepo repository.LoginAttemptRepository
+	eventRepo   repository.SecurityEventRepository
+	config      *config.Config
+}
+
+func NewLoginGuard(attemptRepo repository.LoginAttemptRepository, eventRepo repository.SecurityEventRepository, cfg *config.Config) LoginGuard {
+	return &loginGuard{
+		attemptRepo: attemptRepo,
+		eventRepo:   eventRepo,
+		config:      cfg,
+	}
+}
+
+// Check returns an error if a login for the email from the IP may not be
+// attempted now.
+func (g *loginGuard) Check(email, ip string) error {
+	now := time.Now()
+
+	byIP, err := g.attemptRepo.Find(domain.AttemptsByIP, ip)
+	if err != nil {
+		return err
+	}
+	if byIP != nil && byIP.LockedUntil != nil && now.Before(*byIP.LockedUntil) {
+		return domain.ErrLoginLocked
+	}
+
+	byAccount, err := g.attemptRepo.Find(domain.AttemptsByAccount, accountKey(email))
+	if err != nil || byAccount == nil {
+		return err
+	}
+	if byAccount.LockedUntil != nil && now.Before(*byAccount.LockedUntil) {
+		return domain.ErrLoginLocked
+	}
+	if byAccount.WindowStart.After(now.Add(-g.config.Login.Window)) &&
+		now.Before(byAccount.LastFailureAt.Add(g.delay(byAccount.Failures))) {
+		return domain.ErrLoginThrottled
+	}
+	return nil
+}
+
+// Failure counts a failed login and locks the account or IP once it reaches
+// its threshold. UserID is set if the email belongs to an account.
+func (g *loginGuard) Failure(email, ip string, userID *string) error {
+	now := time.Now()
+	until := now.Add(g.config.Login.LockDuration)
+	key := accountKey(email)
+
+	byAccount, err := g.attemptRepo.RecordFailure(domain.AttemptsByAccount, key, now, g.config.Login.Window)
+	if err != nil {
+		return err
+	}
+	if byAccount.Failures >= g.config.Login.AccountLockThreshold {
+		if err := g.attemptRepo.Lock(domain.AttemptsByAccount, key, now, until); err != nil {
+			return err
+		}
+		if err := g.record(domain.EventAccountLocked, userID, &key, &ip, until); err != nil {
+			return err
+		}
+	}
+
+	byIP, err := g.attemptRepo.RecordFailure(domain.AttemptsByIP, ip, now, g.config.Login.Window)
+	if err != nil {
+		return err
+	}
+	if byIP.Failures >= g.config.Login.IPLockThreshold {
+		if err := g.attemptRepo.Lock(domain.AttemptsByIP, ip, now, until); err != nil {
+			return err
+		}
+		if err := g.record(domain.EventIPLocked, nil, nil, &ip, until); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Success clears the failures of the account. Those of the IP stay, as many
+// users may share it.
+func (g *loginGuard) Success(email string) error {
+	return g.attemptRepo.Reset(domain.AttemptsByAccount, accountKey(email))
+}
+
+func (g *loginGuard) Events(filter domain.SecurityEventFilter) (*domain.SecurityEventPage, error) {
+	// Fetch one extra event to find out whether another page follows
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	events, err := g.eventRepo.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := g.eventRepo.Count(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.SecurityEventPage{Data: events, TotalCount: total}
+	if len(events) > limit {
+		page.Data = events[:limit]
+		page.HasMore = true
+	}
+	return page, nil
+}
+
+// delay returns how long an account with the given number of failures has
+// to wait after the last one.
+func (g *loginGuard) delay(failures int) time.Duration {
+	cfg := g.config.Login
+	if failures < cfg.DelayAfter {
+		return 0
+	}
+	delay := cfg.BaseDelay
+	for i := cfg.DelayAfter; i < failures && delay < cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > cfg.MaxDelay {
+		delay = cfg.MaxDelay
+	}
+	return delay
+}
+
+func (g *loginGuard) record(eventType domain.SecurityEventType, userID, email, ip *string, lockedUntil time.Time) error {
+	return g.eventRepo.Create(&domain.SecurityEvent{
+		ID:          uuid.New().String(),
+		Type:        eventType,
+		UserID:      userID,
+		Email:       email,
+		IP:          ip,
+		LockedUntil: &lockedUntil,
+		CreatedAt:   time.Now(),
+	})
+}
+
+// accountKey is the key under which the failures for an email are counted.
+func accountKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
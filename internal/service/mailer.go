@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+)
+
+// Mailer sends emails to users, e.g. through an SMTP server.
+type Mailer interface {
+	Send(msg domain.Email) error
+}
+
+// NewMailer returns the mailer selected by the configuration.
+func NewMailer(cfg config.MailConfig) Mailer {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg)
+	case "file":
+		return NewFileMailer(cfg.File, cfg.From)
+	}
+	return NewLogMailer()
+}
+
+// sendInBackground sends the email without waiting for the mailer, so that
+// the time a request takes does not tell whether an email was sent. Failures
+// are logged.
+func sendInBackground(m Mailer, msg domain.Email) {
+	go func() {
+		if err := m.Send(msg); err != nil {
+			log.Printf("Failed to send email %q: %v", msg.Subject, err)
+		}
+	}()
+}
+
+type logMailer struct{}
+
+// NewLogMailer returns a mailer that writes emails to the log instead of
+// sending them.
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(msg domain.Email) error {
+	log.Printf("Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+type fileMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+}
+
+// NewFileMailer returns a mailer that appends emails to a file, in the same
+// format they would be sent in, so tests and local setups can read the
+// tokens they contain.
+func NewFileMailer(path, from string) Mailer {
+	return &fileMailer{path: path, from: from}
+}
+
+func (m *fileMailer) Send(msg domain.Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open mail file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(message(m.from, msg), "\r\n"...)); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+	return nil
+}
+
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer returns a mailer that delivers emails through an SMTP
+// server, using STARTTLS when the server offers it. Without a username it
+// does not authenticate.
+func NewSMTPMailer(cfg config.MailConfig) Mailer {
+	m := &smtpMailer{
+		addr: net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		from: cfg.From,
+	}
+	if cfg.SMTPUsername != "" {
+		m.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return m
+}
+
+func (m *smtpMailer) Send(msg domain.Email) error {
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, message(m.from, msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// message formats the email as a plain-text RFC 5322 message.
+func message(from string, msg domain.Email) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+	"task-management-api/internal/totp"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// mfaSkew is the number of time steps a code may be off, to allow for clock
+// drift between server and phone.
+const mfaSkew = 1
+
+// MFAService manages TOTP second factors and checks them during login.
+type MFAService interface {
+	Status(userID string) (*domain.MFAStatus, error)
+	Enroll(userID string, req domain.EnrollMFARequest) (*domain.MFAEnrollment, error)
+	Confirm(userID string, req domain.ConfirmMFARequest) (*domain.RecoveryCodes, error)
+	Disable(userID string, req domain.DisableMFARequest) error
+	RegenerateRecoveryCodes(userID string, req domain.RegenerateRecoveryCodesRequest) (*domain.RecoveryCodes, error)
+	Reset(userID string) error
+	StartChallenge(userID string, newPasswordHash *string) (*domain.MFAChallengeResponse, error)
+	FindChallenge(token string) (*domain.MFAChallenge, error)
+	CompleteChallenge(challenge *domain.MFAChallenge, req domain.VerifyMFARequest) error
+}
+
+type mfaService struct {
+	userRepo repository.UserRepository
+	mfaRepo  repository.MFARepository
+	config   *config.Config
+}
+
+func NewMFAService(userRepo repository.UserRepository, mfaRepo repository.MFARepository, cfg *config.Config) MFAService {
+	return &mfaService{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		config:   cfg,
+	}
+}
+
+func (s *mfaService) Status(userID string) (*domain.MFAStatus, error) {
+	mfa, err := s.mfaRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil || mfa.ConfirmedAt == nil {
+		return &domain.MFAStatus{}, nil
+	}
+
+	left, err := s.mfaRepo.CountRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.MFAStatus{Enabled: true, ConfirmedAt: mfa.ConfirmedAt, RecoveryCodesLeft: left}, nil
+}
+
+// Enroll creates a new secret for the user. It only takes effect once a code
+// generated from it is confirmed; enrolling again replaces an unconfirmed
+// secret.
+func (s *mfaService) Enroll(userID string, req domain.EnrollMFARequest) (*domain.MFAEnrollment, error) {
+	user, err := s.checkPassword(userID, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.mfaRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ConfirmedAt != nil {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.seal(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa := &domain.UserMFA{UserID: userID, Secret: sealed, CreatedAt: time.Now()}
+	if err := s.mfaRepo.Save(mfa); err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.config.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables the second factor once the user proves they can generate
+// codes, and returns the first set of recovery codes.
+func (s *mfaService) Confirm(userID string, req domain.ConfirmMFARequest) (*domain.RecoveryCodes, error) {
+	mfa, err := s.mfaRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, domain.ErrMFANotEnrolled
+	}
+	if mfa.ConfirmedAt != nil {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+	if err := s.checkCode(mfa, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, records, err := newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.Confirm(userID, time.Now(), records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable removes the second factor. It takes the password and a current
+// code, so a stolen session alone cannot turn it off.
+func (s *mfaService) Disable(userID string, req domain.DisableMFARequest) error {
+	if _, err := s.checkPassword(userID, req.Password); err != nil {
+		return err
+	}
+	mfa, err := s.enabled(userID)
+	if err != nil {
+		return err
+	}
+
+	// Codes from the authenticator app are all digits, recovery codes are not
+	if len(req.Code) == totp.Digits {
+		err = s.checkCode(mfa, req.Code)
+	} else {
+		err = s.checkRecoveryCode(userID, req.Code)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.mfaRepo.Delete(userID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of the user.
+func (s *mfaService) RegenerateRecoveryCodes(userID string, req domain.RegenerateRecoveryCodesRequest) (*domain.RecoveryCodes, error) {
+	if _, err := s.checkPassword(userID, req.Password); err != nil {
+		return nil, err
+	}
+	if _, err := s.enabled(userID); err != nil {
+		return nil, err
+	}
+
+	codes, records, err := newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Reset removes the second factor without any checks, for admins helping
+// users who lost their device and recovery codes.
+func (s *mfaService) Reset(userID string) error {
+	return s.mfaRepo.Delete(userID)
+}
+
+// StartChallenge returns nil if the user has no second factor. Otherwise it
+// returns a challenge token with which the login is completed; a new password
+// hash given here is kept on the challenge until then. Users who entered too
+// many wrong codes recently get no challenge.
+func (s *mfaService) StartChallenge(userID string, newPasswordHash *string) (*domain.MFAChallengeResponse, error) {
+	mfa, err := s.mfaRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil || mfa.ConfirmedAt == nil {
+		return nil, nil
+	}
+	if s.locked(mfa, time.Now()) {
+		return nil, domain.ErrMFALocked
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &domain.MFAChallenge{
+		ID:              uuid.New().String(),
+		UserID:          userID,
+		TokenHash:       hashToken(token),
+		NewPasswordHash: newPasswordHash,
+		ExpiresAt:       time.Now().Add(s.config.MFA.ChallengeExpiry),
+		CreatedAt:       time.Now(),
+	}
+	if err := s.mfaRepo.CreateChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAChallengeResponse{
+		MFARequired:    true,
+		ChallengeToken: token,
+		ExpiresAt:      challenge.ExpiresAt,
+	}, nil
+}
+
+// FindChallenge returns the pending challenge of the token.
+func (s *mfaService) FindChallenge(token string) (*domain.MFAChallenge, error) {
+	challenge, err := s.mfaRepo.FindChallengeByHash(hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil || time.Now().After(challenge.ExpiresAt) {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+	return challenge, nil
+}
+
+// CompleteChallenge checks the code for the challenge. A challenge is used up
+// by a correct code or by too many wrong ones; wrong codes are also counted
+// for the user, across challenges.
+func (s *mfaService) CompleteChallenge(challenge *domain.MFAChallenge, req domain.VerifyMFARequest) error {
+	if challenge.Attempts >= s.config.MFA.MaxAttempts {
+		if _, err := s.mfaRepo.ConsumeChallenge(challenge.ID); err != nil {
+			return err
+		}
+		return domain.ErrInvalidMFAChallenge
+	}
+
+	// The second factor may have been reset since the password step
+	mfa, err := s.enabled(challenge.UserID)
+	if errors.Is(err, domain.ErrMFANotEnabled) {
+		return domain.ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return err
+	}
+	if s.locked(mfa, time.Now()) {
+		return domain.ErrMFALocked
+	}
+
+	if req.RecoveryCode != "" {
+		err = s.checkRecoveryCode(challenge.UserID, req.RecoveryCode)
+	} else {
+		err = s.checkCode(mfa, req.Code)
+	}
+	if errors.Is(err, domain.ErrInvalidMFACode) {
+		if err := s.mfaRepo.AddChallengeAttempt(challenge.ID); err != nil {
+			return err
+		}
+		if err := s.mfaRepo.RecordFailure(challenge.UserID, time.Now(), s.config.MFA.LockDuration); err != nil {
+			return err
+		}
+		return domain.ErrInvalidMFACode
+	}
+	if err != nil {
+		return err
+	}
+	if mfa.FailedAttempts > 0 {
+		if err := s.mfaRepo.ResetFailures(challenge.UserID); err != nil {
+			return err
+		}
+	}
+
+	consumed, err := s.mfaRepo.ConsumeChallenge(challenge.ID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return domain.ErrInvalidMFAChallenge
+	}
+	return nil
+}
+
+// locked reports whether the user entered too many wrong login codes within
+// the lock duration before now.
+func (s *mfaService) locked(mfa *domain.UserMFA, now time.Time) bool {
+	return mfa.FailedAttempts >= s.config.MFA.MaxFailures &&
+		mfa.LastFailureAt != nil && now.Before(mfa.LastFailureAt.Add(s.config.MFA.LockDuration))
+}
+
+func (s *mfaService) enabled(userID string) (*domain.UserMFA, error) {
+	mfa, err := s.mfaRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil || mfa.ConfirmedAt == nil {
+		return nil, domain.ErrMFANotEnabled
+	}
+	return mfa, nil
+}
+
+// checkCode accepts a TOTP code that has not been used before.
+func (s *mfaService) checkCode(mfa *domain.UserMFA, code string) error {
+	secret, err := s.open(mfa.Secret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), mfaSkew)
+	if !ok {
+		return domain.ErrInvalidMFACode
+	}
+	fresh, err := s.mfaRepo.UseStep(mfa.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return domain.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *mfaService) checkRecoveryCode(userID, code string) error {
+	used, err := s.mfaRepo.UseRecoveryCode(userID, hashToken(normalizeRecoveryCode(code)), time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *mfaService) checkPassword(userID, password string) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return nil, domain.ErrWrongPassword
+	}
+	return user, nil
+}
+
+// seal encrypts a TOTP secret with AES-GCM under the configured key.
+func (s *mfaService) seal(secret string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *mfaService) open(sealed string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return "", errors.New("failed to decode secret")
+	}
+	secret, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return string(secret), nil
+}
+
+func (s *mfaService) cipher() (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(s.config.MFA.EncryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes returns a fresh set of recovery codes, formatted for the
+// user, and the records to store for them.
+func newRecoveryCodes(userID string) (*domain.RecoveryCodes, []domain.RecoveryCode, error) {
+	codes := make([]string, domain.RecoveryCodeCount)
+	records := make([]domain.RecoveryCode, domain.RecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		raw := strings.ToLower(recoveryEncoding.EncodeToString(b))
+		codes[i] = raw[:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:]
+		records[i] = domain.RecoveryCode{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			CodeHash:  hashToken(raw),
+			CreatedAt: time.Now(),
+		}
+	}
+	return &domain.RecoveryCodes{RecoveryCodes: codes}, records, nil
+}
+
+// normalizeRecoveryCode accepts codes with or without dashes, spaces and in
+// any case.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+)
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"abcd-efgh-ijkl-mnop", "abcdefghijklmnop"},
+		{"ABCD-EFGH-IJKL-MNOP", "abcdefghijklmnop"},
+		{"abcdefghijklmnop", "abcdefghijklmnop"},
+		{" abcd efgh ijkl mnop ", "abcdefghijklmnop"},
+		{"Abcd - Efgh - Ijkl - Mnop", "abcdefghijklmnop"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeRecoveryCode(tt.code); got != tt.want {
+			t.Errorf("normalizeRecoveryCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestNewRecoveryCodesMatchNormalizedInput(t *testing.T) {
+	codes, records, err := newRecoveryCodes("user-1")
+	if err != nil {
+		t.Fatalf("newRecoveryCodes() error = %v", err)
+	}
+	if len(codes.RecoveryCodes) != len(records) {
+		t.Fatalf("got %d codes and %d records", len(codes.RecoveryCodes), len(records))
+	}
+
+	for i, code := range codes.RecoveryCodes {
+		if len(code) != 19 {
+			t.Errorf("code %q has length %d, want 19", code, len(code))
+		}
+		if got := hashToken(normalizeRecoveryCode(code)); got != records[i].CodeHash {
+			t.Errorf("code %q does not match its stored hash", code)
+		}
+	}
+}
+
+func TestMFALocked(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := &mfaService{config: &config.Config{MFA: config.MFAConfig{MaxFailures: 3, LockDuration: 15 * time.Minute}}}
+	at := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+
+	tests := []struct {
+		name string
+		mfa  domain.UserMFA
+		want bool
+	}{
+		{"no failures", domain.UserMFA{}, false},
+		{"below the limit", domain.UserMFA{FailedAttempts: 2, LastFailureAt: at(time.Minute)}, false},
+		{"at the limit", domain.UserMFA{FailedAttempts: 3, LastFailureAt: at(time.Minute)}, true},
+		{"above the limit", domain.UserMFA{FailedAttempts: 7, LastFailureAt: at(14 * time.Minute)}, true},
+		{"lock has passed", domain.UserMFA{FailedAttempts: 3, LastFailureAt: at(15 * time.Minute)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.locked(&tt.mfa, now); got != tt.want {
+				t.Errorf("locked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type OrgService interface {
+	Create(req domain.CreateOrgRequest, userID string) (*domain.Organization, error)
+	List(userID string) ([]domain.Organization, error)
+	GetByID(id, userID string) (*domain.Organization, error)
+	Delete(id, userID string) error
+	Members(id, userID string) ([]domain.Membership, error)
+	AddMember(id string, req domain.AddMemberRequest, userID string) (*domain.Membership, error)
+	UpdateMember(id, memberID string, req domain.UpdateMemberRequest, userID string) (*domain.Membership, error)
+	RemoveMember(id, memberID, userID string) error
+}
+
+type orgService struct {
+	orgRepo  repository.OrgRepository
+	userRepo repository.UserRepository
+	policy   *policy.Policy
+}
+
+func NewOrgService(orgRepo repository.OrgRepository, userRepo repository.UserRepository, p *policy.Policy) OrgService {
+	return &orgService{
+		orgRepo:  orgRepo,
+		userRepo: userRepo,
+		policy:   p,
+	}
+}
+
+// Create sets up an organization with the creating user as its owner.
+func (s *orgService) Create(req domain.CreateOrgRequest, userID string) (*domain.Organization, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, domain.ErrOrgNameRequired
+	}
+	if len(name) > 100 {
+		return nil, domain.FieldValidationError("name_too_long", "name", "name must be at most 100 characters")
+	}
+
+	org := &domain.Organization{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedBy: &userID,
+		Role:      domain.OrgRoleOwner,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	owner := &domain.Membership{OrgID: org.ID, UserID: userID, Role: domain.OrgRoleOwner, CreatedAt: time.Now()}
+
+	if err := s.orgRepo.Create(org, owner); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+func (s *orgService) List(userID string) ([]domain.Organization, error) {
+	return s.orgRepo.FindByUser(userID)
+}
+
+func (s *orgService) GetByID(id, userID string) (*domain.Organization, error) {
+	subject, err := s.authorize(id, userID, policy.OrgRead)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, domain.ErrOrgNotFound
+	}
+	org.Role = subject.OrgRole
+	return org, nil
+}
+
+// Delete removes an organization with all of its tasks. Only owners may do
+// this.
+func (s *orgService) Delete(id, userID string) error {
+	if _, err := s.authorize(id, userID, policy.OrgDelete); err != nil {
+		return err
+	}
+	return s.orgRepo.Delete(id)
+}
+
+func (s *orgService) Members(id, userID string) ([]domain.Membership, error) {
+	if _, err := s.authorize(id, userID, policy.OrgRead); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.FindMembers(id)
+}
+
+// AddMember adds an existing user, found by email, to the organization.
+// Admins manage members; only owners may add further owners.
+func (s *orgService) AddMember(id string, req domain.AddMemberRequest, userID string) (*domain.Membership, error) {
+	subject, err := s.authorize(id, userID, policy.OrgManageMembers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(subject, req.Role); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrMemberUserUnknown
+	}
+
+	existing, err := s.orgRepo.FindMembership(id, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrMemberExists
+	}
+
+	member := &domain.Membership{
+		OrgID:     id,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      req.Role,
+		CreatedAt: time.Now(),
+	}
+	if err := s.orgRepo.AddMember(member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// UpdateMember changes a member's role. Only owners may change the role of
+// an owner or make someone an owner, and the last owner cannot step down.
+func (s *orgService) UpdateMember(id, memberID string, req domain.UpdateMemberRequest, userID string) (*domain.Membership, error) {
+	subject, err := s.authorize(id, userID, policy.OrgManageMembers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(subject, req.Role); err != nil {
+		return nil, err
+	}
+
+	if _, err := uuid.Parse(memberID); err != nil {
+		return nil, domain.ErrMemberNotFound
+	}
+	member, err := s.orgRepo.FindMembership(id, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, domain.ErrMemberNotFound
+	}
+	if member.Role == domain.OrgRoleOwner {
+		if !s.policy.Can(subject, policy.OrgManageOwners, policy.OrgResource(id)) {
+			return nil, domain.ErrOrgRoleRequired
+		}
+		if req.Role != domain.OrgRoleOwner {
+			if err := s.checkNotLastOwner(id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.orgRepo.UpdateMemberRole(id, memberID, req.Role); err != nil {
+		return nil, err
+	}
+
+	member.Role = req.Role
+	return member, nil
+}
+
+// RemoveMember removes a member from the organization. Members may always
+// leave on their own; removing others requires the admin role, and owners
+// can only be removed by owners. The last owner cannot leave.
+func (s *orgService) RemoveMember(id, memberID, userID string) error {
+	subject, err := s.authorize(id, userID, policy.OrgRead)
+	if err != nil {
+		return err
+	}
+
+	if _, err := uuid.Parse(memberID); err != nil {
+		return domain.ErrMemberNotFound
+	}
+	member, err := s.orgRepo.FindMembership(id, memberID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return domain.ErrMemberNotFound
+	}
+
+	if memberID != userID {
+		action := policy.OrgManageMembers
+		if member.Role == domain.OrgRoleOwner {
+			action = policy.OrgManageOwners
+		}
+		if !s.policy.Can(subject, action, policy.OrgResource(id)) {
+			return domain.ErrOrgRoleRequired
+		}
+	}
+	if member.Role == domain.OrgRoleOwner {
+		if err := s.checkNotLastOwner(id); err != nil {
+			return err
+		}
+	}
+
+	return s.orgRepo.RemoveMember(id, memberID)
+}
+
+// authorize checks that the user may perform the action in the organization,
+// acting with their role there, which need not be the organization of the
+// session. Organizations the user does not belong to are reported as not
+// found.
+func (s *orgService) authorize(orgID, userID string, action policy.Action) (policy.Subject, error) {
+	if _, err := uuid.Parse(orgID); err != nil {
+		return policy.Subject{}, domain.ErrOrgNotFound
+	}
+	member, err := s.orgRepo.FindMembership(orgID, userID)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+	if member == nil {
+		return policy.Subject{}, domain.ErrOrgNotFound
+	}
+
+	subject := policy.Subject{UserID: userID, OrgID: orgID, OrgRole: member.Role}
+	if !s.policy.Can(subject, action, policy.OrgResource(orgID)) {
+		return policy.Subject{}, domain.ErrOrgRoleRequired
+	}
+	return subject, nil
+}
+
+func (s *orgService) checkNotLastOwner(orgID string) error {
+	owners, err := s.orgRepo.CountOwners(orgID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrLastOwner
+	}
+	return nil
+}
+
+// checkGrant reports whether the subject may hand out the role.
+func (s *orgService) checkGrant(subject policy.Subject, role domain.OrgRole) error {
+	if !role.IsValid() {
+		return domain.ErrInvalidOrgRole
+	}
+	if role == domain.OrgRoleOwner && !s.policy.Can(subject, policy.OrgManageOwners, policy.OrgResource(subject.OrgID)) {
+		return domain.ErrOrgRoleRequired
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordResetService lets users who forgot their password choose a new one
+// through a token mailed to them.
+type PasswordResetService interface {
+	Forgot(req domain.ForgotPasswordRequest) error
+	Reset(req domain.ResetPasswordRequest) error
+}
+
+type passwordResetService struct {
+	userRepo           repository.UserRepository
+	tokenRepo          repository.TokenRepository
+	resetRepo          repository.PasswordResetRepository
+	accessTokenService AccessTokenService
+	mailer             Mailer
+	config             *config.Config
+}
+
+func NewPasswordResetService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, resetRepo repository.PasswordResetRepository, accessTokenService AccessTokenService, mailer Mailer, cfg *config.Config) PasswordResetService {
+	return &passwordResetService{
+		userRepo:           userRepo,
+		tokenRepo:          tokenRepo,
+		resetRepo:          resetRepo,
+		accessTokenService: accessTokenService,
+		mailer:             mailer,
+		config:             cfg,
+	}
+}
+
+// Forgot mails a reset token if the email belongs to an enabled account that
+// has not used up its resets for the window. It succeeds either way, so the
+// response does not tell whether the account exists.
+func (s *passwordResetService) Forgot(req domain.ForgotPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.DisabledAt != nil {
+		return nil
+	}
+
+	count, err := s.resetRepo.CountSince(user.ID, time.Now().Add(-s.config.Reset.Window))
+	if err != nil {
+		return err
+	}
+	if count >= s.config.Reset.MaxPerAccount {
+		return nil
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	reset := &domain.PasswordReset{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.config.Reset.Expiry),
+		CreatedAt: time.Now(),
+	}
+	if err := s.resetRepo.Create(reset); err != nil {
+		return err
+	}
+
+	sendInBackground(s.mailer, domain.Email{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use this token to choose a new password:\n\n%s\n\nThe token expires at %s. If you did not ask to reset your password, ignore this email.",
+			token, reset.ExpiresAt.UTC().Format(time.RFC1123)),
+	})
+	return nil
+}
+
+// Reset sets the new password and ends all sessions and personal access
+// tokens of the user. The token
+// and any other pending tokens of the user cannot be used again. It also
+// lifts a password change required by an admin.
+func (s *passwordResetService) Reset(req domain.ResetPasswordRequest) error {
+	now := time.Now()
+	reset, err := s.resetRepo.Consume(hashToken(req.Token), now)
+	if err != nil {
+		return err
+	}
+	if reset == nil {
+		return domain.ErrInvalidPasswordReset
+	}
+
+	user, err := s.userRepo.FindByID(reset.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return domain.ErrInvalidPasswordReset
+	}
+	if user.DisabledAt != nil {
+		return domain.ErrAccountDisabled
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
+		return err
+	}
+	if err := s.resetRepo.InvalidateForUser(user.ID, now); err != nil {
+		return err
+	}
+
+	if err := s.tokenRepo.RevokeAllForUser(user.ID); err != nil {
+		return err
+	}
+	return s.accessTokenService.RevokeAll(user.ID)
+}
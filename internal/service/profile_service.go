@@ -0,0 +1,245 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ProfileService lets users manage their own account.
+type ProfileService interface {
+	Get(userID string) (*domain.User, error)
+	Update(userID string, req domain.UpdateProfileRequest) (*domain.User, error)
+	ChangePassword(claims *Claims, req domain.ChangePasswordRequest) error
+	RequestEmailChange(userID string, req domain.ChangeEmailRequest) error
+	ConfirmEmailChange(req domain.ConfirmEmailChangeRequest) (*domain.User, error)
+	DeleteAccount(userID string, req domain.DeleteAccountRequest) error
+}
+
+type profileService struct {
+	userRepo           repository.UserRepository
+	tokenRepo          repository.TokenRepository
+	emailChangeRepo    repository.EmailChangeRepository
+	accessTokenService AccessTokenService
+	mailer             Mailer
+	config             *config.Config
+}
+
+func NewProfileService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, emailChangeRepo repository.EmailChangeRepository, accessTokenService AccessTokenService, mailer Mailer, cfg *config.Config) ProfileService {
+	return &profileService{
+		userRepo:           userRepo,
+		tokenRepo:          tokenRepo,
+		emailChangeRepo:    emailChangeRepo,
+		accessTokenService: accessTokenService,
+		mailer:             mailer,
+		config:             cfg,
+	}
+}
+
+func (s *profileService) Get(userID string) (*domain.User, error) {
+	return s.find(userID)
+}
+
+func (s *profileService) Update(userID string, req domain.UpdateProfileRequest) (*domain.User, error) {
+	user, err := s.find(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DisplayName != nil {
+		user.DisplayName = emptyToNil(*req.DisplayName)
+	}
+	if req.Timezone != nil {
+		user.Timezone = *req.Timezone
+	}
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+	if req.AvatarURL != nil {
+		user.AvatarURL = emptyToNil(*req.AvatarURL)
+	}
+	user.UpdatedAt = time.Now()
+
+	if err := s.userRepo.UpdateProfile(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ChangePassword sets a new password and ends every session of the user
+// except the one making the change, along with all personal access tokens.
+func (s *profileService) ChangePassword(claims *Claims, req domain.ChangePasswordRequest) error {
+	user, err := s.find(claims.UserID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)) != nil {
+		return domain.ErrWrongCurrentPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
+		return err
+	}
+
+	if err := s.tokenRepo.RevokeOtherSessions(user.ID, claims.SessionID); err != nil {
+		return err
+	}
+	return s.accessTokenService.RevokeAll(user.ID)
+}
+
+// RequestEmailChange mails a confirmation token to the new address. The
+// email of the account only changes once the token is confirmed; a new
+// request replaces a pending one.
+func (s *profileService) RequestEmailChange(userID string, req domain.ChangeEmailRequest) error {
+	user, err := s.find(userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+		return domain.ErrWrongPassword
+	}
+	if req.Email == user.Email {
+		return domain.ErrEmailUnchanged
+	}
+	if err := s.checkEmailAvailable(req.Email); err != nil {
+		return err
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	change := &domain.EmailChange{
+		UserID:    user.ID,
+		NewEmail:  req.Email,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.config.Account.EmailChangeExpiry),
+		CreatedAt: time.Now(),
+	}
+	if err := s.emailChangeRepo.Save(change); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(domain.Email{
+		To:      req.Email,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Use this token to confirm %s as the email address of your account:\n\n%s\n\nThe token expires at %s.",
+			req.Email, token, change.ExpiresAt.UTC().Format(time.RFC1123)),
+	})
+}
+
+// ConfirmEmailChange applies the change the token belongs to, ends all
+// sessions of the user and lets the previous address know about it. Each
+// token can be used once.
+func (s *profileService) ConfirmEmailChange(req domain.ConfirmEmailChangeRequest) (*domain.User, error) {
+	change, err := s.emailChangeRepo.FindByHash(hashToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	if change == nil || time.Now().After(change.ExpiresAt) {
+		return nil, domain.ErrInvalidEmailChange
+	}
+
+	user, err := s.find(change.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		return nil, domain.ErrInvalidEmailChange
+	}
+	// The address may have been taken since the change was requested
+	if err := s.checkEmailAvailable(change.NewEmail); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateEmail(user.ID, change.NewEmail); err != nil {
+		return nil, err
+	}
+	if err := s.emailChangeRepo.Delete(user.ID); err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.RevokeAllForUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	// The change is applied, so a mail failure must not fail the request
+	sendInBackground(s.mailer, domain.Email{
+		To:      user.Email,
+		Subject: "Your email address was changed",
+		Body:    fmt.Sprintf("The email address of your account was changed to %s.", change.NewEmail),
+	})
+
+	user.Email = change.NewEmail
+	return user, nil
+}
+
+// DeleteAccount ends all sessions of the user and anonymizes or removes the
+// account, as the retention policy says. The last admin cannot delete their
+// account.
+func (s *profileService) DeleteAccount(userID string, req domain.DeleteAccountRequest) error {
+	user, err := s.find(userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+		return domain.ErrWrongPassword
+	}
+	// Demoting first keeps two admins from deleting themselves at once
+	if user.Role == domain.RoleAdmin {
+		demoted, err := s.userRepo.Demote(user.ID)
+		if err != nil {
+			return err
+		}
+		if !demoted {
+			return domain.ErrLastAdmin
+		}
+	}
+
+	if err := s.tokenRepo.RevokeAllForUser(user.ID); err != nil {
+		return err
+	}
+
+	if s.config.Account.Retention == domain.RetainNothing {
+		return s.userRepo.Delete(user.ID, nil)
+	}
+	return s.userRepo.Anonymize(user.ID, fmt.Sprintf("deleted-%s@deleted.invalid", user.ID), time.Now())
+}
+
+func (s *profileService) checkEmailAvailable(email string) error {
+	existing, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return domain.ErrUserExists
+	}
+	return nil
+}
+
+func (s *profileService) find(id string) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func emptyToNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
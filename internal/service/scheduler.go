@@ -0,0 +1,153 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// scheduler keeps pending timers in a min-heap ordered by due time and runs
+// a single goroutine that sleeps until the earliest one is due. It replaces
+// one sleeping goroutine per job, so thousands of timers cost one timer.
+type scheduler struct {
+	mu    sync.Mutex
+	items timerHeap
+	index map[string]*timerItem
+	wake  chan struct{}
+	fire  func(id string)
+}
+
+type timerItem struct {
+	id  string
+	at  time.Time
+	pos int
+}
+
+func newScheduler(fire func(id string)) *scheduler {
+	return &scheduler{
+		index: make(map[string]*timerItem),
+		wake:  make(chan struct{}, 1),
+		fire:  fire,
+	}
+}
+
+// Schedule registers id to fire at the given time. Scheduling an id that is
+// already pending moves it to the new time.
+func (s *scheduler) Schedule(id string, at time.Time) {
+	s.mu.Lock()
+	if item, ok := s.index[id]; ok {
+		item.at = at
+		heap.Fix(&s.items, item.pos)
+	} else {
+		item := &timerItem{id: id, at: at}
+		heap.Push(&s.items, item)
+		s.index[id] = item
+	}
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Cancel removes a pending timer. It is a no-op if id is not scheduled.
+func (s *scheduler) Cancel(id string) {
+	s.mu.Lock()
+	if item, ok := s.index[id]; ok {
+		heap.Remove(&s.items, item.pos)
+		delete(s.index, id)
+	}
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Len returns the number of pending timers.
+func (s *scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// Run fires timers as they become due until ctx is cancelled.
+func (s *scheduler) Run(ctx context.Context) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		var next <-chan time.Time
+		if len(s.items) > 0 {
+			resetTimer(timer, time.Until(s.items[0].at))
+			next = timer.C
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-next:
+			for _, id := range s.popDue(time.Now()) {
+				s.fire(id)
+			}
+		}
+	}
+}
+
+func (s *scheduler) popDue(now time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []string
+	for len(s.items) > 0 && !s.items[0].at.After(now) {
+		item := heap.Pop(&s.items).(*timerItem)
+		delete(s.index, item.id)
+		due = append(due, item.id)
+	}
+	return due
+}
+
+func (s *scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func resetTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	if d < 0 {
+		d = 0
+	}
+	t.Reset(d)
+}
+
+type timerHeap []*timerItem
+
+func (h timerHeap) Len() int           { return len(h) }
+func (h timerHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+
+func (h *timerHeap) Push(x interface{}) {
+	item := x.(*timerItem)
+	item.pos = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *timerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchedulerPopDue(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type op struct {
+		cancel bool
+		id     string
+		at     time.Duration
+	}
+
+	tests := []struct {
+		name    string
+		ops     []op
+		now     time.Duration
+		want    []string
+		wantLen int
+	}{
+		{
+			name:    "due in order of time",
+			ops:     []op{{id: "c", at: 3}, {id: "a", at: 1}, {id: "b", at: 2}},
+			now:     3,
+			want:    []string{"a", "b", "c"},
+			wantLen: 0,
+		},
+		{
+			name:    "later timers stay pending",
+			ops:     []op{{id: "a", at: 1}, {id: "b", at: 5}, {id: "c", at: 2}},
+			now:     2,
+			want:    []string{"a", "c"},
+			wantLen: 1,
+		},
+		{
+			name:    "rescheduling moves the timer",
+			ops:     []op{{id: "a", at: 1}, {id: "b", at: 2}, {id: "a", at: 3}},
+			now:     3,
+			want:    []string{"b", "a"},
+			wantLen: 0,
+		},
+		{
+			name:    "rescheduling keeps one timer per id",
+			ops:     []op{{id: "a", at: 1}, {id: "a", at: 5}},
+			now:     3,
+			want:    nil,
+			wantLen: 1,
+		},
+		{
+			name:    "cancelled timers do not fire",
+			ops:     []op{{id: "a", at: 1}, {id: "b", at: 2}, {id: "c", at: 3}, {cancel: true, id: "b"}},
+			now:     3,
+			want:    []string{"a", "c"},
+			wantLen: 0,
+		},
+		{
+			name:    "cancelling the earliest timer",
+			ops:     []op{{id: "a", at: 1}, {id: "b", at: 2}, {cancel: true, id: "a"}},
+			now:     1,
+			want:    nil,
+			wantLen: 1,
+		},
+		{
+			name:    "cancelling an unknown id is a no-op",
+			ops:     []op{{id: "a", at: 1}, {cancel: true, id: "x"}},
+			now:     1,
+			want:    []string{"a"},
+			wantLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScheduler(func(string) {})
+			for _, o := range tt.ops {
+				if o.cancel {
+					s.Cancel(o.id)
+				} else {
+					s.Schedule(o.id, base.Add(o.at*time.Minute))
+				}
+			}
+
+			if got := s.popDue(base.Add(tt.now * time.Minute)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("popDue() = %v, want %v", got, tt.want)
+			}
+			if got := s.Len(); got != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", got, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	fired := make(chan string, 3)
+	s := newScheduler(func(id string) { fired <- id })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	now := time.Now()
+	s.Schedule("later", now.Add(40*time.Millisecond))
+	s.Schedule("cancelled", now.Add(10*time.Millisecond))
+	s.Schedule("sooner", now.Add(20*time.Millisecond))
+	s.Cancel("cancelled")
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case id := <-fired:
+			got = append(got, id)
+		case <-time.After(time.Second):
+			t.Fatalf("timers did not fire, got %v", got)
+		}
+	}
+
+	if want := []string{"sooner", "later"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fired %v, want %v", got, want)
+	}
+	if n := s.Len(); n != 0 {
+		t.Errorf("Len() = %d after firing, want 0", n)
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+
+	"task-management-api/internal/config"
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+)
+
+// subtaskRules enforces the task hierarchy limits and the configured
+// behaviour for subtasks of deleted and completed tasks. It is shared by the
+// task service and the worker so both complete tasks the same way.
+type subtaskRules struct {
+	taskRepo  repository.TaskRepository
+	workflows *workflowResolver
+	config    config.SubtaskConfig
+}
+
+// checkParent verifies that task may be placed under parentID: the parent
+// must exist in the same organization, must not be the task or one of its
+// subtasks, and the resulting tree must stay within the depth limit.
+func (r *subtaskRules) checkParent(task *domain.Task, parentID string) error {
+	parent, err := r.taskRepo.FindByID(parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil || parent.OrgID != task.OrgID {
+		return domain.ErrParentNotFound
+	}
+
+	ancestors, err := r.taskRepo.FindAncestorIDs(parentID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ancestors {
+		if id == task.ID {
+			return domain.ErrSubtaskCycle
+		}
+	}
+
+	// A task that is moved brings its own subtasks along
+	descendants, err := r.taskRepo.FindDescendants(task.ID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors)+subtreeHeight(task.ID, descendants) > r.config.MaxDepth {
+		return domain.ErrSubtaskTooDeep
+	}
+	return nil
+}
+
+// checkComplete reports whether task may be completed. With the block action
+// every subtask, at any depth, has to be completed first.
+func (r *subtaskRules) checkComplete(task *domain.Task) error {
+	if r.config.OnComplete != domain.SubtaskBlock {
+		return nil
+	}
+	descendants, err := r.taskRepo.FindDescendants(task.ID)
+	if err != nil {
+		return err
+	}
+	for _, d := range descendants {
+		if !d.IsClosed() {
+			return domain.ErrSubtasksIncomplete
+		}
+	}
+	return nil
+}
+
+// completed applies the completion action to the subtasks of a task that has
+// just been completed and rolls the change up to its parents.
+func (r *subtaskRules) completed(task *domain.Task) error {
+	switch r.config.OnComplete {
+	case domain.SubtaskCascade:
+		descendants, err := r.taskRepo.FindDescendants(task.ID)
+		if err != nil {
+			return err
+		}
+		for i := range descendants {
+			if descendants[i].IsClosed() {
+				continue
+			}
+			if err := r.close(&descendants[i]); err != nil {
+				return err
+			}
+		}
+	case domain.SubtaskOrphan:
+		if err := r.taskRepo.DetachOpenChildren(task.ID); err != nil {
+			return err
+		}
+	}
+	return r.rollUp(task.ParentID)
+}
+
+// complete checks and completes a task on behalf of the system, e.g. the
+// auto-completion worker. Blocked tasks are not completed.
+func (r *subtaskRules) complete(task *domain.Task) error {
+	if err := checkUnblocked(r.taskRepo, task.ID); err != nil {
+		return err
+	}
+	if err := r.checkComplete(task); err != nil {
+		return err
+	}
+	if err := r.close(task); err != nil {
+		return err
+	}
+	return r.completed(task)
+}
+
+// close moves a task to the done status of its workflow, bypassing the
+// workflow's transitions.
+func (r *subtaskRules) close(task *domain.Task) error {
+	workflow, err := r.workflows.forTask(task)
+	if err != nil {
+		return err
+	}
+	return r.taskRepo.UpdateStatus(task.ID, workflow.DoneStatus(), domain.CategoryClosed)
+}
+
+// rollUp completes the parent once all of its subtasks are done if it uses
+// the subtasks_done auto-completion mode and is not blocked, continuing up
+// the tree.
+func (r *subtaskRules) rollUp(parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+
+	parent, err := r.taskRepo.FindByID(*parentID)
+	if err != nil || parent == nil {
+		return err
+	}
+	if parent.IsClosed() || parent.AutoComplete == nil ||
+		parent.AutoComplete.Mode != domain.AutoCompleteSubtasksDone {
+		return nil
+	}
+
+	progress, err := r.taskRepo.FindSubtaskProgress([]string{parent.ID})
+	if err != nil {
+		return err
+	}
+	if p := progress[parent.ID]; p.Total == 0 || p.Completed < p.Total {
+		return nil
+	}
+	if err := checkUnblocked(r.taskRepo, parent.ID); err != nil {
+		if errors.Is(err, domain.ErrTaskBlocked) {
+			return nil
+		}
+		return err
+	}
+
+	if err := r.close(parent); err != nil {
+		return err
+	}
+	return r.rollUp(parent.ParentID)
+}
+
+// delete removes a task according to the configured delete action.
+func (r *subtaskRules) delete(task *domain.Task) error {
+	switch r.config.OnDelete {
+	case domain.SubtaskCascade:
+		if err := r.taskRepo.DeleteWithDescendants(task.ID); err != nil {
+			return err
+		}
+	case domain.SubtaskBlock:
+		progress, err := r.taskRepo.FindSubtaskProgress([]string{task.ID})
+		if err != nil {
+			return err
+		}
+		if progress[task.ID].Total > 0 {
+			return domain.ErrTaskHasSubtasks
+		}
+		fallthrough
+	default:
+		// The foreign key moves remaining subtasks to the top level
+		if err := r.taskRepo.Delete(task.ID); err != nil {
+			return err
+		}
+	}
+
+	// Removing an open subtask may leave only completed siblings
+	return r.rollUp(task.ParentID)
+}
+
+// subtreeHeight returns the number of levels in the tree rooted at rootID,
+// given all of its descendants.
+func subtreeHeight(rootID string, descendants []domain.Task) int {
+	children := make(map[string][]string)
+	for _, d := range descendants {
+		if d.ParentID != nil {
+			children[*d.ParentID] = append(children[*d.ParentID], d.ID)
+		}
+	}
+
+	var height func(id string, seen map[string]bool) int
+	height = func(id string, seen map[string]bool) int {
+		seen[id] = true
+		deepest := 0
+		for _, child := range children[id] {
+			if !seen[child] {
+				deepest = max(deepest, height(child, seen))
+			}
+		}
+		return deepest + 1
+	}
+	return height(rootID, make(map[string]bool))
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// taskGuard loads tasks from the subject's organization and checks the
+// subject's permission on them. It is shared by the services acting on tasks.
+type taskGuard struct {
+	taskRepo     repository.TaskRepository
+	assigneeRepo repository.AssigneeRepository
+	policy       *policy.Policy
+}
+
+// find loads a task with its assignees and checks that the subject may
+// perform the action on it.
+func (g *taskGuard) find(id string, subject policy.Subject, action policy.Action) (*domain.Task, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	task, err := g.taskRepo.ForOrg(subject.OrgID).FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	assignees, err := g.assigneeRepo.FindByTaskIDs([]string{task.ID})
+	if err != nil {
+		return nil, err
+	}
+	task.Assignees = assignees[task.ID]
+
+	// Authorization check
+	if !g.policy.Can(subject, action, policy.TaskResource(task)) {
+		return nil, domain.ErrTaskAccessDenied
+	}
+	return task, nil
+}
+
+// readable loads the assignees of the tasks and keeps the ones the subject
+// may read.
+func (g *taskGuard) readable(tasks []domain.Task, subject policy.Subject) ([]domain.Task, error) {
+	kept := []domain.Task{}
+	if len(tasks) == 0 {
+		return kept, nil
+	}
+
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	assignees, err := g.assigneeRepo.FindByTaskIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		task.Assignees = assignees[task.ID]
+		if g.policy.Can(subject, policy.TaskRead, policy.TaskResource(&task)) {
+			kept = append(kept, task)
+		}
+	}
+	return kept, nil
+}
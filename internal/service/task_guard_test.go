@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
+)
+
+// IDs that are not UUIDs never reach the repositories, which are left nil
+// here, and are reported as not found.
+func TestInvalidIDsAreNotFound(t *testing.T) {
+	const (
+		valid   = "6f1c7a52-3c1e-4d8e-9a43-1f0c2b5d7e90"
+		invalid = "not-a-uuid"
+	)
+	p := policy.New()
+	subject := policy.Subject{UserID: valid, OrgID: valid, OrgRole: domain.OrgRoleOwner}
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{
+			name: "task",
+			call: func() error {
+				_, err := NewDependencyService(nil, nil, nil, p).List(invalid, subject)
+				return err
+			},
+			want: domain.ErrTaskNotFound,
+		},
+		{
+			name: "label",
+			call: func() error {
+				return NewLabelService(nil, nil, nil, p).Delete(invalid, subject)
+			},
+			want: domain.ErrLabelNotFound,
+		},
+		{
+			name: "workflow",
+			call: func() error {
+				_, err := NewWorkflowService(nil, nil, p).GetByID(invalid, subject)
+				return err
+			},
+			want: domain.ErrWorkflowNotFound,
+		},
+		{
+			name: "organization",
+			call: func() error {
+				_, err := NewOrgService(nil, nil, p).Members(invalid, valid)
+				return err
+			},
+			want: domain.ErrOrgNotFound,
+		},
+		{
+			name: "switch organization",
+			call: func() error {
+				_, err := NewAuthService(nil, nil, nil, nil, nil, nil, nil, nil).SwitchOrg(&Claims{UserID: valid}, invalid)
+				return err
+			},
+			want: domain.ErrOrgNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
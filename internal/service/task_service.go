@@ -1,82 +1,246 @@
 package service
 
 import (
-	"fmt"
+	"errors"
 	"time"
 
+	"task-management-api/internal/config"
 	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
 	"task-management-api/internal/repository"
 
 	"github.com/google/uuid"
 )
 
+// TaskService works on the tasks of the subject's organization. What the
+// subject may do with them is decided by the policy.
 type TaskService interface {
-	Create(req domain.CreateTaskRequest, userID string) (*domain.Task, error)
-	GetByID(id, userID string, isAdmin bool) (*domain.Task, error)
-	List(filter domain.TaskFilter, userID string, isAdmin bool) ([]domain.Task, error)
-	Update(id string, req domain.UpdateTaskRequest, userID string, isAdmin bool) (*domain.Task, error)
-	Delete(id, userID string, isAdmin bool) error
+	Create(req domain.CreateTaskRequest, subject policy.Subject) (*domain.Task, error)
+	GetByID(id string, subject policy.Subject) (*domain.Task, error)
+	List(filter domain.TaskFilter, subject policy.Subject) (*domain.TaskPage, error)
+	Update(id string, req domain.UpdateTaskRequest, subject policy.Subject) (*domain.Task, error)
+	Delete(id string, subject policy.Subject) error
+	Children(id string, subject policy.Subject) ([]domain.Task, error)
+	Tree(id string, subject policy.Subject) (*domain.TaskNode, error)
+	Reassign(id string, req domain.ReassignRequest, subject policy.Subject) (*domain.Task, error)
+	AssignmentHistory(id string, subject policy.Subject) ([]domain.AssignmentChange, error)
+	Permissions(id string, subject policy.Subject) ([]policy.Action, error)
 }
 
 type taskService struct {
-	taskRepo repository.TaskRepository
+	orgID        string
+	taskRepo     repository.TaskRepository
+	labelRepo    repository.LabelRepository
+	workflowRepo repository.WorkflowRepository
+	assigneeRepo repository.AssigneeRepository
+	orgRepo      repository.OrgRepository
+	policy       *policy.Policy
+	guard        *taskGuard
+	workflows    *workflowResolver
+	subtasks     *subtaskRules
 }
 
-func NewTaskService(taskRepo repository.TaskRepository) TaskService {
-	return &taskService{taskRepo: taskRepo}
+func NewTaskService(taskRepo repository.TaskRepository, labelRepo repository.LabelRepository, workflowRepo repository.WorkflowRepository, assigneeRepo repository.AssigneeRepository, orgRepo repository.OrgRepository, p *policy.Policy, cfg *config.Config) TaskService {
+	workflows := &workflowResolver{workflowRepo: workflowRepo}
+	return &taskService{
+		taskRepo:     taskRepo,
+		labelRepo:    labelRepo,
+		workflowRepo: workflowRepo,
+		assigneeRepo: assigneeRepo,
+		orgRepo:      orgRepo,
+		policy:       p,
+		guard:        &taskGuard{taskRepo: taskRepo, assigneeRepo: assigneeRepo, policy: p},
+		workflows:    workflows,
+		subtasks:     &subtaskRules{taskRepo: taskRepo, workflows: workflows, config: cfg.Subtasks},
+	}
+}
+
+// inOrg returns a copy of the service whose task repository, including the
+// one used by the subtask rules, only sees the organization's tasks. Every
+// method starts by switching to it.
+func (s *taskService) inOrg(orgID string) *taskService {
+	scoped := *s
+	scoped.orgID = orgID
+	scoped.taskRepo = s.taskRepo.ForOrg(orgID)
+	subtasks := *s.subtasks
+	subtasks.taskRepo = scoped.taskRepo
+	scoped.subtasks = &subtasks
+	return &scoped
 }
 
-func (s *taskService) Create(req domain.CreateTaskRequest, userID string) (*domain.Task, error) {
+func (s *taskService) Create(req domain.CreateTaskRequest, subject policy.Subject) (*domain.Task, error) {
+	s = s.inOrg(subject.OrgID)
+
+	if !s.policy.Can(subject, policy.TaskCreate, policy.OrgResource(subject.OrgID)) {
+		return nil, domain.ErrOrgRoleRequired
+	}
+
+	if req.AutoComplete != nil && !req.AutoComplete.IsValid() {
+		return nil, domain.ErrInvalidAutoComplete
+	}
+	if req.RemindAt != nil && req.DueAt != nil && req.RemindAt.After(*req.DueAt) {
+		return nil, domain.ErrInvalidRemindAt
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = domain.PriorityMedium
+	}
+	if !priority.IsValid() {
+		return nil, domain.ErrInvalidPriority
+	}
+
 	task := &domain.Task{
-		ID:          uuid.New().String(),
-		UserID:      userID,
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      domain.StatusPending,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:           uuid.New().String(),
+		OrgID:        subject.OrgID,
+		UserID:       subject.UserID,
+		Title:        req.Title,
+		Description:  req.Description,
+		Priority:     priority,
+		AutoComplete: req.AutoComplete,
+		DueAt:        req.DueAt,
+		RemindAt:     req.RemindAt,
+		Labels:       []domain.Label{},
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	// New tasks start in the initial status of their workflow
+	workflow := domain.DefaultWorkflow
+	if req.WorkflowID != nil && *req.WorkflowID != "" && *req.WorkflowID != domain.DefaultWorkflowID {
+		if _, err := uuid.Parse(*req.WorkflowID); err != nil {
+			return nil, domain.ErrWorkflowNotFound
+		}
+		found, err := s.workflowRepo.FindByID(*req.WorkflowID)
+		if err != nil {
+			return nil, err
+		}
+		if found == nil {
+			return nil, domain.ErrWorkflowNotFound
+		}
+		res, err := workflowResource(s.orgRepo, found, subject.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		if !s.policy.Can(subject, policy.WorkflowRead, res) {
+			return nil, domain.ErrWorkflowNotFound
+		}
+		workflow = found
+		task.WorkflowID = &found.ID
+	}
+	task.Status = workflow.InitialStatus
+	task.StatusCategory, _ = workflow.Category(workflow.InitialStatus)
+
+	if req.ParentID != nil && *req.ParentID != "" {
+		if err := s.checkParent(task, *req.ParentID, subject); err != nil {
+			return nil, err
+		}
+		task.ParentID = req.ParentID
+	}
+
+	// Tasks are assigned to their creator unless told otherwise
+	assigneeIDs := req.AssigneeIDs
+	if len(assigneeIDs) == 0 {
+		assigneeIDs = []string{subject.UserID}
+	}
+	assigneeIDs, err := s.checkAssignees(assigneeIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := s.taskRepo.Create(task); err != nil {
 		return nil, err
 	}
+	if err := s.assigneeRepo.Set(task.ID, assigneeIDs, subject.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadDetails([]*domain.Task{task}); err != nil {
+		return nil, err
+	}
 
 	return task, nil
 }
 
-func (s *taskService) GetByID(id, userID string, isAdmin bool) (*domain.Task, error) {
-	task, err := s.taskRepo.FindByID(id)
+func (s *taskService) GetByID(id string, subject policy.Subject) (*domain.Task, error) {
+	s = s.inOrg(subject.OrgID)
+
+	task, err := s.guard.find(id, subject, policy.TaskRead)
 	if err != nil {
 		return nil, err
 	}
-	if task == nil {
-		return nil, fmt.Errorf("task not found")
-	}
 
-	// Authorization check
-	if !isAdmin && task.UserID != userID {
-		return nil, fmt.Errorf("unauthorized access")
+	if err := s.loadDetails([]*domain.Task{task}); err != nil {
+		return nil, err
 	}
 
 	return task, nil
 }
 
-func (s *taskService) List(filter domain.TaskFilter, userID string, isAdmin bool) ([]domain.Task, error) {
-	return s.taskRepo.FindAll(filter, userID, isAdmin)
-}
+// List returns one page of tasks. One extra row is fetched to find out
+// whether another page follows without a separate query.
+func (s *taskService) List(filter domain.TaskFilter, subject policy.Subject) (*domain.TaskPage, error) {
+	s = s.inOrg(subject.OrgID)
+
+	// Without task:read:all only own and assigned tasks are listed
+	readAll := s.policy.Can(subject, policy.TaskReadAll, policy.OrgResource(subject.OrgID))
+
+	if len(filter.Sort) == 0 {
+		filter.Sort = domain.DefaultTaskSort
+		if filter.Search != "" {
+			filter.Sort = domain.DefaultSearchSort
+		}
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultPageSize
+	}
 
-func (s *taskService) Update(id string, req domain.UpdateTaskRequest, userID string, isAdmin bool) (*domain.Task, error) {
-	task, err := s.taskRepo.FindByID(id)
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	tasks, err := s.taskRepo.FindAll(filter, subject.UserID, readAll)
 	if err != nil {
 		return nil, err
 	}
-	if task == nil {
-		return nil, fmt.Errorf("task not found")
+
+	page := &domain.TaskPage{}
+	if len(tasks) > pageSize {
+		tasks = tasks[:pageSize]
+		page.HasMore = true
+		page.NextCursor = domain.NewTaskCursor(filter.Sort, &tasks[len(tasks)-1]).Encode()
 	}
 
-	// Authorization check
-	if !isAdmin && task.UserID != userID {
-		return nil, fmt.Errorf("unauthorized access")
+	refs := make([]*domain.Task, len(tasks))
+	for i := range tasks {
+		refs[i] = &tasks[i]
+	}
+	if err := s.loadDetails(refs); err != nil {
+		return nil, err
+	}
+	page.Data = tasks
+
+	if filter.WithTotal {
+		total, err := s.taskRepo.Count(filter, subject.UserID, readAll)
+		if err != nil {
+			return nil, err
+		}
+		page.TotalCount = &total
+	}
+
+	return page, nil
+}
+
+func (s *taskService) Update(id string, req domain.UpdateTaskRequest, subject policy.Subject) (*domain.Task, error) {
+	s = s.inOrg(subject.OrgID)
+
+	// Everyone who may update a task may change its status; assignees may
+	// change nothing else
+	task, err := s.guard.find(id, subject, policy.TaskUpdateStatus)
+	if err != nil {
+		return nil, err
+	}
+	if !req.OnlyStatus() && !s.policy.Can(subject, policy.TaskUpdate, policy.TaskResource(task)) {
+		return nil, domain.ErrAssigneeStatusOnly
 	}
 
 	// Update fields
@@ -86,11 +250,85 @@ func (s *taskService) Update(id string, req domain.UpdateTaskRequest, userID str
 	if req.Description != nil {
 		task.Description = *req.Description
 	}
-	if req.Status != nil {
-		if !req.Status.IsValid() {
-			return nil, fmt.Errorf("invalid status")
+	// Status changes must follow the task's workflow
+	var transition *domain.WorkflowTransition
+	completing := false
+	if req.Status != nil && *req.Status != task.Status {
+		workflow, err := s.workflows.forTask(task)
+		if err != nil {
+			return nil, err
 		}
+		category, ok := workflow.Category(*req.Status)
+		if !ok {
+			return nil, domain.ErrInvalidStatus
+		}
+		transition = workflow.Transition(task.Status, *req.Status)
+		if transition == nil {
+			return nil, domain.InvalidTransitionError(task.Status, *req.Status)
+		}
+		completing = category == domain.CategoryClosed && !task.IsClosed()
 		task.Status = *req.Status
+		task.StatusCategory = category
+	}
+	if req.Priority != nil {
+		if !req.Priority.IsValid() {
+			return nil, domain.ErrInvalidPriority
+		}
+		task.Priority = *req.Priority
+	}
+	if req.AutoComplete != nil {
+		if !req.AutoComplete.IsValid() {
+			return nil, domain.ErrInvalidAutoComplete
+		}
+		task.AutoComplete = req.AutoComplete
+	}
+	if req.DueAt != nil {
+		task.DueAt = req.DueAt
+		// Moving the due date into the future clears the overdue flag
+		if req.DueAt.After(time.Now()) {
+			task.OverdueAt = nil
+		}
+	}
+	if req.RemindAt != nil {
+		task.RemindAt = req.RemindAt
+		task.RemindedAt = nil
+	}
+	if task.RemindAt != nil && task.DueAt != nil && task.RemindAt.After(*task.DueAt) {
+		return nil, domain.ErrInvalidRemindAt
+	}
+	// Required fields may be set by the same request
+	if transition != nil {
+		if err := transition.CheckRequiredFields(task); err != nil {
+			return nil, err
+		}
+	}
+
+	// The old parent may be all done once this task moves away from it
+	previousParent := task.ParentID
+	if req.ParentID != nil {
+		if *req.ParentID == "" {
+			task.ParentID = nil
+		} else {
+			if err := s.checkParent(task, *req.ParentID, subject); err != nil {
+				return nil, err
+			}
+			task.ParentID = req.ParentID
+		}
+	}
+
+	if completing {
+		if err := checkUnblocked(s.taskRepo, task.ID); err != nil {
+			return nil, err
+		}
+		if err := s.subtasks.checkComplete(task); err != nil {
+			return nil, err
+		}
+		if s.subtasks.config.OnComplete == domain.SubtaskCascade {
+			err := s.checkSubtree(task.ID, subject, policy.TaskUpdateStatus, func(d *domain.Task) bool { return !d.IsClosed() })
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 	task.UpdatedAt = time.Now()
 
@@ -98,22 +336,283 @@ func (s *taskService) Update(id string, req domain.UpdateTaskRequest, userID str
 		return nil, err
 	}
 
+	if completing {
+		if err := s.subtasks.completed(task); err != nil {
+			return nil, err
+		}
+	}
+	if req.ParentID != nil {
+		if err := s.subtasks.rollUp(previousParent); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.loadDetails([]*domain.Task{task}); err != nil {
+		return nil, err
+	}
+
 	return task, nil
 }
 
-func (s *taskService) Delete(id, userID string, isAdmin bool) error {
-	task, err := s.taskRepo.FindByID(id)
+// Reassign replaces the assignees of a task. Only the creator and admins may
+// reassign; every change is recorded with the user who made it.
+func (s *taskService) Reassign(id string, req domain.ReassignRequest, subject policy.Subject) (*domain.Task, error) {
+	s = s.inOrg(subject.OrgID)
+
+	task, err := s.guard.find(id, subject, policy.TaskAssign)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.AssigneeIDs) == 0 {
+		return nil, domain.ErrAssigneesRequired
+	}
+
+	assigneeIDs, err := s.checkAssignees(req.AssigneeIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assigneeRepo.Set(task.ID, assigneeIDs, subject.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadDetails([]*domain.Task{task}); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// AssignmentHistory returns who was assigned to and removed from a task, and
+// by whom.
+func (s *taskService) AssignmentHistory(id string, subject policy.Subject) ([]domain.AssignmentChange, error) {
+	s = s.inOrg(subject.OrgID)
+
+	if _, err := s.guard.find(id, subject, policy.TaskRead); err != nil {
+		return nil, err
+	}
+	return s.assigneeRepo.FindHistory(id)
+}
+
+// Permissions returns the actions the subject may perform on a task it can
+// see.
+func (s *taskService) Permissions(id string, subject policy.Subject) ([]policy.Action, error) {
+	s = s.inOrg(subject.OrgID)
+
+	task, err := s.guard.find(id, subject, policy.TaskRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.policy.Allowed(subject, policy.TaskResource(task), policy.TaskActions...), nil
+}
+
+// checkAssignees removes duplicate user IDs and verifies that every user is
+// a member of the organization and that the task stays within the assignee
+// limit.
+func (s *taskService) checkAssignees(userIDs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	unique := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > domain.MaxAssignees {
+		return nil, domain.ErrTooManyAssignees
+	}
+
+	for _, id := range unique {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, domain.ErrAssigneeNotFound
+		}
+		member, err := s.orgRepo.FindMembership(s.orgID, id)
+		if err != nil {
+			return nil, err
+		}
+		if member == nil {
+			return nil, domain.ErrAssigneeNotFound
+		}
+	}
+	return unique, nil
+}
+
+// loadDetails fills in the labels, assignees, subtask progress and blocked
+// flag of all given tasks with one query each.
+func (s *taskService) loadDetails(tasks []*domain.Task) error {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	labels, err := s.labelRepo.FindByTaskIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	assignees, err := s.assigneeRepo.FindByTaskIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	progress, err := s.taskRepo.FindSubtaskProgress(ids)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := s.taskRepo.FindBlocked(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		task.Labels = labels[task.ID]
+		if task.Labels == nil {
+			task.Labels = []domain.Label{}
+		}
+		task.Assignees = assignees[task.ID]
+		if task.Assignees == nil {
+			task.Assignees = []domain.Assignee{}
+		}
+		task.Progress = nil
+		if p, ok := progress[task.ID]; ok {
+			task.Progress = &p
+		}
+		task.Blocked = blocked[task.ID]
+	}
+	return nil
+}
+
+// Delete removes a task. Assignees may not delete the tasks assigned to them.
+func (s *taskService) Delete(id string, subject policy.Subject) error {
+	s = s.inOrg(subject.OrgID)
+
+	task, err := s.guard.find(id, subject, policy.TaskDelete)
 	if err != nil {
 		return err
 	}
-	if task == nil {
-		return fmt.Errorf("task not found")
+	if s.subtasks.config.OnDelete == domain.SubtaskCascade {
+		if err := s.checkSubtree(task.ID, subject, policy.TaskDelete, func(*domain.Task) bool { return true }); err != nil {
+			return err
+		}
 	}
 
-	// Authorization check
-	if !isAdmin && task.UserID != userID {
-		return fmt.Errorf("unauthorized access")
+	return s.subtasks.delete(task)
+}
+
+// checkParent checks that the subject may update the task it wants to place
+// a task under, before the hierarchy rules. A parent the subject cannot read
+// is reported as not found.
+func (s *taskService) checkParent(task *domain.Task, parentID string, subject policy.Subject) error {
+	parent, err := s.guard.find(parentID, subject, policy.TaskRead)
+	if errors.Is(err, domain.ErrTaskNotFound) || errors.Is(err, domain.ErrTaskAccessDenied) {
+		return domain.ErrParentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !s.policy.Can(subject, policy.TaskUpdate, policy.TaskResource(parent)) {
+		return domain.ErrTaskAccessDenied
+	}
+	return s.subtasks.checkParent(task, parentID)
+}
+
+// checkSubtree requires the action on every subtask, at any depth, that a
+// cascade would change along with the task.
+func (s *taskService) checkSubtree(id string, subject policy.Subject, action policy.Action, affected func(*domain.Task) bool) error {
+	descendants, err := s.taskRepo.FindDescendants(id)
+	if err != nil || len(descendants) == 0 {
+		return err
+	}
+
+	ids := make([]string, len(descendants))
+	for i, d := range descendants {
+		ids[i] = d.ID
+	}
+	assignees, err := s.assigneeRepo.FindByTaskIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range descendants {
+		d := &descendants[i]
+		if !affected(d) {
+			continue
+		}
+		d.Assignees = assignees[d.ID]
+		if !s.policy.Can(subject, action, policy.TaskResource(d)) {
+			return domain.ErrSubtaskAccess
+		}
+	}
+	return nil
+}
+
+// Children returns the direct subtasks of a task that the subject may read.
+func (s *taskService) Children(id string, subject policy.Subject) ([]domain.Task, error) {
+	s = s.inOrg(subject.OrgID)
+
+	if _, err := s.GetByID(id, subject); err != nil {
+		return nil, err
+	}
+
+	children, err := s.taskRepo.FindChildren(id)
+	if err != nil {
+		return nil, err
+	}
+	children, err = s.guard.readable(children, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*domain.Task, len(children))
+	for i := range children {
+		refs[i] = &children[i]
+	}
+	if err := s.loadDetails(refs); err != nil {
+		return nil, err
+	}
+
+	return children, nil
+}
+
+// Tree returns a task with all of its subtasks nested below it. Subtasks the
+// subject may not read are left out together with their own subtasks.
+func (s *taskService) Tree(id string, subject policy.Subject) (*domain.TaskNode, error) {
+	s = s.inOrg(subject.OrgID)
+
+	root, err := s.GetByID(id, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.taskRepo.FindDescendants(id)
+	if err != nil {
+		return nil, err
+	}
+	descendants, err = s.guard.readable(descendants, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*domain.Task, len(descendants))
+	for i := range descendants {
+		refs[i] = &descendants[i]
+	}
+	if err := s.loadDetails(refs); err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]*domain.TaskNode{
+		root.ID: {Task: *root, Children: []*domain.TaskNode{}},
+	}
+	for _, task := range descendants {
+		nodes[task.ID] = &domain.TaskNode{Task: task, Children: []*domain.TaskNode{}}
+	}
+	// Descendants are ordered by creation, so children keep that order
+	for _, task := range descendants {
+		if parent, ok := nodes[*task.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[task.ID])
+		}
 	}
 
-	return s.taskRepo.Delete(id)
+	return nodes[root.ID], nil
 }
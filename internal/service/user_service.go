@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// UserService is the admin view of user accounts.
+type UserService interface {
+	List(filter domain.UserFilter) (*domain.UserPage, error)
+	GetByID(id string) (*domain.UserDetail, error)
+	UpdateRole(id string, req domain.UpdateUserRoleRequest) (*domain.User, error)
+	Disable(id, adminID string) (*domain.User, error)
+	Enable(id string) (*domain.User, error)
+	RequirePasswordReset(id string) (*domain.User, error)
+	ResetMFA(id string) (*domain.User, error)
+	Delete(id string, disposition domain.TaskDisposition, reassignTo, adminID string) error
+}
+
+type userService struct {
+	userRepo           repository.UserRepository
+	tokenRepo          repository.TokenRepository
+	adminService       AdminService
+	mfaService         MFAService
+	accessTokenService AccessTokenService
+}
+
+func NewUserService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, adminService AdminService, mfaService MFAService, accessTokenService AccessTokenService) UserService {
+	return &userService{
+		userRepo:           userRepo,
+		tokenRepo:          tokenRepo,
+		adminService:       adminService,
+		mfaService:         mfaService,
+		accessTokenService: accessTokenService,
+	}
+}
+
+func (s *userService) List(filter domain.UserFilter) (*domain.UserPage, error) {
+	// Fetch one extra user to find out whether another page follows
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	users, err := s.userRepo.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.userRepo.Count(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.UserPage{Data: users, TotalCount: total}
+	if len(users) > limit {
+		page.Data = users[:limit]
+		page.HasMore = true
+	}
+	return page, nil
+}
+
+func (s *userService) GetByID(id string) (*domain.UserDetail, error) {
+	user, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.userRepo.CountTasks(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserDetail{User: *user, TaskCounts: *counts}, nil
+}
+
+// UpdateRole promotes or demotes a user, with the same rules as the admin
+// endpoints.
+func (s *userService) UpdateRole(id string, req domain.UpdateUserRoleRequest) (*domain.User, error) {
+	if !req.Role.IsValid() {
+		return nil, domain.ErrInvalidUserRole
+	}
+	if req.Role == domain.RoleAdmin {
+		return s.adminService.Promote(id)
+	}
+	return s.adminService.Demote(id)
+}
+
+// Disable blocks the user from logging in and ends all of their sessions.
+func (s *userService) Disable(id, adminID string) (*domain.User, error) {
+	if id == adminID {
+		return nil, domain.ErrOwnAccount
+	}
+	user, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		return user, nil
+	}
+
+	now := time.Now()
+	if err := s.userRepo.SetDisabled(id, &now); err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.RevokeAllForUser(id); err != nil {
+		return nil, err
+	}
+
+	user.DisabledAt = &now
+	return user, nil
+}
+
+// Enable lifts a block. Accounts deleted by their users stay disabled.
+func (s *userService) Enable(id string) (*domain.User, error) {
+	user, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.DisabledAt == nil {
+		return user, nil
+	}
+
+	if err := s.userRepo.SetDisabled(id, nil); err != nil {
+		return nil, err
+	}
+
+	user.DisabledAt = nil
+	return user, nil
+}
+
+// RequirePasswordReset ends the user's sessions and personal access tokens
+// and makes them choose a new password at their next login.
+func (s *userService) RequirePasswordReset(id string) (*domain.User, error) {
+	user, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetPasswordResetRequired(id, true); err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.RevokeAllForUser(id); err != nil {
+		return nil, err
+	}
+	if err := s.accessTokenService.RevokeAll(id); err != nil {
+		return nil, err
+	}
+
+	user.PasswordResetRequired = true
+	return user, nil
+}
+
+// ResetMFA removes the user's second factor, so they can log in with the
+// password alone and enrol again.
+func (s *userService) ResetMFA(id string) (*domain.User, error) {
+	user, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaService.Reset(id); err != nil {
+		return nil, err
+	}
+
+	user.MFAEnabled = false
+	return user, nil
+}
+
+// Delete removes the user for good. The tasks they created are deleted or
+// reassigned to another enabled user, as the disposition says.
+func (s *userService) Delete(id string, disposition domain.TaskDisposition, reassignTo, adminID string) error {
+	if !disposition.IsValid() {
+		return domain.ErrInvalidTaskDisposition
+	}
+	if id == adminID {
+		return domain.ErrOwnAccount
+	}
+	if _, err := s.find(id); err != nil {
+		return err
+	}
+
+	var target *string
+	if disposition == domain.TasksReassign {
+		if reassignTo == id {
+			return domain.ErrInvalidReassignTarget
+		}
+		user, err := s.find(reassignTo)
+		if errors.Is(err, domain.ErrUserNotFound) || (err == nil && user.DisabledAt != nil) {
+			return domain.ErrInvalidReassignTarget
+		}
+		if err != nil {
+			return err
+		}
+		target = &user.ID
+	}
+
+	return s.userRepo.Delete(id, target)
+}
+
+func (s *userService) find(id string) (*domain.User, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
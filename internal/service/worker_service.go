@@ -2,39 +2,80 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 
 	"task-management-api/internal/config"
 	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
 	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	workerCount = 5
+
+	// scheduleHorizon is how far ahead queued jobs are loaded into the
+	// in-memory scheduler. It must exceed the scanner interval.
+	scheduleHorizon = 2 * time.Minute
 )
 
 type WorkerService interface {
 	Start(ctx context.Context)
-	EnqueueTask(taskID string)
+	EnqueueTask(task *domain.Task) error
+	ScheduleDueDates(task *domain.Task) error
+	ScheduledJobs() int
 }
 
 type workerService struct {
-	taskRepo     repository.TaskRepository
-	config       *config.Config
-	taskQueue    chan string
-	processedIDs sync.Map
-	wg           sync.WaitGroup
+	taskRepo  repository.TaskRepository
+	jobRepo   repository.JobRepository
+	tokenRepo repository.TokenRepository
+	mfaRepo   repository.MFARepository
+	events    EventPublisher
+	policy    *policy.Policy
+	config    *config.Config
+	subtasks  *subtaskRules
+	scheduler *scheduler
+	due       chan struct{}
+	wg        sync.WaitGroup
 }
 
-func NewWorkerService(taskRepo repository.TaskRepository, cfg *config.Config) WorkerService {
-	return &workerService{
+func NewWorkerService(taskRepo repository.TaskRepository, jobRepo repository.JobRepository, tokenRepo repository.TokenRepository, mfaRepo repository.MFARepository, workflowRepo repository.WorkflowRepository, events EventPublisher, p *policy.Policy, cfg *config.Config) WorkerService {
+	w := &workerService{
 		taskRepo:  taskRepo,
+		jobRepo:   jobRepo,
+		tokenRepo: tokenRepo,
+		mfaRepo:   mfaRepo,
+		events:    events,
+		policy:    p,
 		config:    cfg,
-		taskQueue: make(chan string, 100),
+		due:       make(chan struct{}, workerCount),
+		subtasks: &subtaskRules{
+			taskRepo:  taskRepo,
+			workflows: &workflowResolver{workflowRepo: workflowRepo},
+			config:    cfg.Subtasks,
+		},
 	}
+	w.scheduler = newScheduler(w.onDue)
+	return w
 }
 
 func (w *workerService) Start(ctx context.Context) {
+	// Start the delay scheduler and load jobs that are about to become due
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.scheduler.Run(ctx)
+	}()
+	w.scheduleUpcoming()
+
 	// Start worker goroutines
-	for i := 0; i < 5; i++ {
+	for i := 0; i < workerCount; i++ {
 		w.wg.Add(1)
 		go w.worker(ctx)
 	}
@@ -46,64 +87,320 @@ func (w *workerService) Start(ctx context.Context) {
 	log.Println("Worker service started")
 }
 
-func (w *workerService) EnqueueTask(taskID string) {
+// EnqueueTask schedules the auto-completion of a task according to its
+// policy, replacing any earlier schedule. The job is stored in the database,
+// so it survives restarts and is shared between replicas.
+func (w *workerService) EnqueueTask(task *domain.Task) error {
+	runAt, ok := task.AutoCompleteAt(w.defaultAutoCompleteDelay())
+	if !ok {
+		return w.cancel(domain.JobAutoCompleteTask, task.ID)
+	}
+	if _, err := w.enqueue(domain.JobAutoCompleteTask, task.ID, runAt); err != nil {
+		return err
+	}
+	log.Printf("Task %s enqueued for auto-completion at %s", task.ID, runAt.Format(time.RFC3339))
+	return nil
+}
+
+// ScheduleDueDates schedules the reminder and overdue jobs of a task, and
+// cancels them when the task no longer needs them.
+func (w *workerService) ScheduleDueDates(task *domain.Task) error {
+	if task.NeedsReminder() {
+		if _, err := w.enqueue(domain.JobTaskReminder, task.ID, *task.RemindAt); err != nil {
+			return err
+		}
+	} else if err := w.cancel(domain.JobTaskReminder, task.ID); err != nil {
+		return err
+	}
+
+	if task.NeedsOverdueMark() {
+		if _, err := w.enqueue(domain.JobTaskOverdue, task.ID, *task.DueAt); err != nil {
+			return err
+		}
+	} else if err := w.cancel(domain.JobTaskOverdue, task.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *workerService) defaultAutoCompleteDelay() time.Duration {
+	return time.Duration(w.config.Worker.AutoCompleteMinutes) * time.Minute
+}
+
+// ScheduledJobs returns the number of timers pending in the scheduler.
+func (w *workerService) ScheduledJobs() int {
+	return w.scheduler.Len()
+}
+
+func (w *workerService) enqueue(jobType domain.JobType, taskID string, runAt time.Time) (*domain.Job, error) {
+	job := &domain.Job{
+		ID:          uuid.New().String(),
+		Type:        jobType,
+		TaskID:      taskID,
+		Status:      domain.JobQueued,
+		RunAt:       runAt,
+		MaxAttempts: w.config.Worker.MaxAttempts,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := w.jobRepo.Enqueue(job); err != nil {
+		return nil, err
+	}
+	w.schedule(job.ID, job.RunAt)
+	return job, nil
+}
+
+// cancel removes the queued job of the type for the task, together with its
+// timer.
+func (w *workerService) cancel(jobType domain.JobType, taskID string) error {
+	ids, err := w.jobRepo.Cancel(jobType, taskID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		w.scheduler.Cancel(id)
+	}
+	return nil
+}
+
+// schedule times a job in memory if it is due within the horizon. Later jobs
+// are loaded by the scanner once they come close, which keeps the scheduler
+// small and bounds how long the timer of a job deleted with its task lingers.
+func (w *workerService) schedule(jobID string, runAt time.Time) {
+	if runAt.After(time.Now().Add(scheduleHorizon)) {
+		// A job moved beyond the horizon must not fire at its old time
+		w.scheduler.Cancel(jobID)
+		return
+	}
+	w.scheduler.Schedule(jobID, runAt)
+}
+
+// onDue is called by the scheduler when a timer fires and wakes a worker
+// to claim the job. Jobs are still claimed through the database, so a timer
+// firing on several replicas runs the job only once.
+func (w *workerService) onDue(jobID string) {
 	select {
-	case w.taskQueue <- taskID:
-		log.Printf("Task %s enqueued for auto-completion", taskID)
+	case w.due <- struct{}{}:
 	default:
-		log.Printf("Task queue full, skipping task %s", taskID)
+	}
+}
+
+// scheduleUpcoming loads queued jobs due within the horizon into the
+// scheduler, including jobs enqueued by other replicas or before a restart.
+func (w *workerService) scheduleUpcoming() {
+	jobs, err := w.jobRepo.FindUpcoming(time.Now().Add(scheduleHorizon))
+	if err != nil {
+		log.Printf("Error loading upcoming jobs: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		w.schedule(job.ID, job.RunAt)
 	}
 }
 
 func (w *workerService) worker(ctx context.Context) {
 	defer w.wg.Done()
 
+	ticker := time.NewTicker(w.config.Worker.PollInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("Worker shutting down")
 			return
-		case taskID := <-w.taskQueue:
-			w.processTask(taskID)
+		case <-w.due:
+			w.runDueJobs(ctx)
+		case <-ticker.C:
+			w.runDueJobs(ctx)
 		}
 	}
 }
 
-func (w *workerService) processTask(taskID string) {
-	// Check if already processed
-	if _, exists := w.processedIDs.LoadOrStore(taskID, true); exists {
+// runDueJobs keeps claiming jobs one at a time until the queue has nothing
+// due, so a backlog is drained without waiting for further ticks.
+func (w *workerService) runDueJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		jobs, err := w.jobRepo.ClaimDue(1)
+		if err != nil {
+			log.Printf("Error claiming jobs: %v", err)
+			return
+		}
+		if len(jobs) == 0 {
+			return
+		}
+		for _, job := range jobs {
+			w.processJob(job)
+		}
+	}
+}
+
+func (w *workerService) processJob(job domain.Job) {
+	var err error
+	switch job.Type {
+	case domain.JobAutoCompleteTask:
+		err = w.processTask(job.TaskID)
+	case domain.JobTaskReminder:
+		err = w.processReminder(job.TaskID)
+	case domain.JobTaskOverdue:
+		err = w.processOverdue(job.TaskID)
+	default:
+		err = fmt.Errorf("unknown job type %q", job.Type)
+	}
+
+	// A job rescheduled while it ran is queued again for its new time
+	if err == nil {
+		requeued, err := w.jobRepo.Complete(job.ID, job.RunAt)
+		if err != nil {
+			log.Printf("Error completing job %s: %v", job.ID, err)
+		} else if requeued != nil {
+			w.schedule(job.ID, *requeued)
+		}
+		return
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		log.Printf("Job %s failed permanently after %d attempts: %v", job.ID, job.Attempts, err)
+		requeued, err := w.jobRepo.Fail(job.ID, job.RunAt, err.Error())
+		if err != nil {
+			log.Printf("Error marking job %s as dead: %v", job.ID, err)
+		} else if requeued != nil {
+			w.schedule(job.ID, *requeued)
+		}
+		return
+	}
+
+	runAt := time.Now().Add(w.backoff(job.Attempts))
+	log.Printf("Job %s failed (attempt %d/%d), retrying at %s: %v", job.ID, job.Attempts, job.MaxAttempts, runAt.Format(time.RFC3339), err)
+	next, err := w.jobRepo.Retry(job.ID, job.RunAt, runAt, err.Error())
+	if err != nil {
+		log.Printf("Error rescheduling job %s: %v", job.ID, err)
 		return
 	}
+	w.schedule(job.ID, next)
+}
 
-	// Wait for the configured duration
-	time.Sleep(time.Duration(w.config.Worker.AutoCompleteMinutes) * time.Minute)
+// backoff doubles the retry delay with every attempt, capped at one hour.
+func (w *workerService) backoff(attempts int) time.Duration {
+	delay := w.config.Worker.RetryBaseDelay
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
 
+func (w *workerService) processTask(taskID string) error {
 	// Fetch task to verify it still needs auto-completion
 	task, err := w.taskRepo.FindByID(taskID)
 	if err != nil {
-		log.Printf("Error fetching task %s: %v", taskID, err)
-		w.processedIDs.Delete(taskID)
-		return
+		return fmt.Errorf("error fetching task %s: %w", taskID, err)
 	}
 
 	if task == nil {
 		log.Printf("Task %s not found (may have been deleted)", taskID)
-		w.processedIDs.Delete(taskID)
-		return
+		return nil
+	}
+	if !w.policy.Can(policy.System, policy.TaskUpdateStatus, policy.TaskResource(task)) {
+		log.Printf("Worker may not change the status of task %s, skipping auto-completion", taskID)
+		return nil
 	}
 
-	// Only auto-complete if still pending or in progress
-	if task.Status == domain.StatusPending || task.Status == domain.StatusInProgress {
-		if err := w.taskRepo.UpdateStatus(taskID, domain.StatusCompleted); err != nil {
-			log.Printf("Error auto-completing task %s: %v", taskID, err)
-		} else {
-			log.Printf("Task %s auto-completed successfully", taskID)
+	// The policy may have changed since the job was scheduled
+	runAt, ok := task.AutoCompleteAt(w.defaultAutoCompleteDelay())
+	if !ok {
+		log.Printf("Task %s no longer auto-completes on a timer, skipping", taskID)
+		return nil
+	}
+	if runAt.After(time.Now()) {
+		log.Printf("Task %s is not due for auto-completion until %s, skipping", taskID, runAt.Format(time.RFC3339))
+		return nil
+	}
+
+	// Only auto-complete if not already in a closed status
+	if !task.IsClosed() {
+		err := w.subtasks.complete(task)
+		if errors.Is(err, domain.ErrSubtasksIncomplete) || errors.Is(err, domain.ErrTaskBlocked) {
+			// The scanner picks the task up again once it can be completed
+			log.Printf("Task %s cannot be completed yet (%v), skipping auto-completion", taskID, err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error auto-completing task %s: %w", taskID, err)
 		}
+		log.Printf("Task %s auto-completed successfully", taskID)
 	} else {
 		log.Printf("Task %s already completed, skipping auto-completion", taskID)
 	}
 
-	w.processedIDs.Delete(taskID)
+	return nil
+}
+
+// processReminder emits a reminder event once the task's remind_at passes.
+func (w *workerService) processReminder(taskID string) error {
+	task, err := w.taskRepo.FindByID(taskID)
+	if err != nil {
+		return fmt.Errorf("error fetching task %s: %w", taskID, err)
+	}
+	if task == nil || !task.NeedsReminder() || task.RemindAt.After(time.Now()) {
+		return nil
+	}
+	if !w.policy.Can(policy.System, policy.TaskRead, policy.TaskResource(task)) {
+		return nil
+	}
+
+	now := time.Now()
+	if err := w.events.Publish(domain.TaskEvent{
+		Type:       domain.EventTaskReminder,
+		TaskID:     task.ID,
+		UserID:     task.UserID,
+		Title:      task.Title,
+		DueAt:      task.DueAt,
+		OccurredAt: now,
+	}); err != nil {
+		return fmt.Errorf("error publishing reminder for task %s: %w", taskID, err)
+	}
+
+	if err := w.taskRepo.MarkReminded(taskID, now); err != nil {
+		return fmt.Errorf("error marking task %s reminded: %w", taskID, err)
+	}
+	return nil
+}
+
+// processOverdue flags a task as overdue once its due_at passes without the
+// task having been completed.
+func (w *workerService) processOverdue(taskID string) error {
+	task, err := w.taskRepo.FindByID(taskID)
+	if err != nil {
+		return fmt.Errorf("error fetching task %s: %w", taskID, err)
+	}
+	if task == nil || !task.NeedsOverdueMark() || task.DueAt.After(time.Now()) {
+		return nil
+	}
+	if !w.policy.Can(policy.System, policy.TaskUpdateStatus, policy.TaskResource(task)) {
+		return nil
+	}
+
+	now := time.Now()
+	if err := w.taskRepo.MarkOverdue(taskID, now); err != nil {
+		return fmt.Errorf("error marking task %s overdue: %w", taskID, err)
+	}
+	log.Printf("Task %s is overdue", taskID)
+
+	if err := w.events.Publish(domain.TaskEvent{
+		Type:       domain.EventTaskOverdue,
+		TaskID:     task.ID,
+		UserID:     task.UserID,
+		Title:      task.Title,
+		DueAt:      task.DueAt,
+		OccurredAt: now,
+	}); err != nil {
+		log.Printf("Error publishing overdue event for task %s: %v", taskID, err)
+	}
+	return nil
 }
 
 func (w *workerService) scanner(ctx context.Context) {
@@ -118,27 +415,106 @@ func (w *workerService) scanner(ctx context.Context) {
 			log.Println("Scanner shutting down")
 			return
 		case <-ticker.C:
+			w.requeueStaleJobs()
 			w.scanPendingTasks()
+			w.scanDueDates()
+			w.scheduleUpcoming()
+			w.pruneTokens()
+			w.pruneChallenges()
+			w.pruneJobs()
 		}
 	}
 }
 
+func (w *workerService) requeueStaleJobs() {
+	count, err := w.jobRepo.RequeueStale(w.config.Worker.LockTimeout)
+	if err != nil {
+		log.Printf("Error requeueing stale jobs: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Requeued %d stale jobs", count)
+	}
+}
+
+// pruneTokens deletes expired refresh tokens and revocation records, which
+// would otherwise pile up with every login and refresh.
+func (w *workerService) pruneTokens() {
+	count, err := w.tokenRepo.DeleteExpired(time.Now())
+	if err != nil {
+		log.Printf("Error pruning expired tokens: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Pruned %d expired tokens", count)
+	}
+}
+
+// pruneJobs deletes done and dead jobs once they are older than the retention
+// period. A task whose auto-completion job died is picked up by the scanner
+// again after that.
+func (w *workerService) pruneJobs() {
+	count, err := w.jobRepo.DeleteFinished(time.Now().Add(-w.config.Worker.JobRetention))
+	if err != nil {
+		log.Printf("Error pruning finished jobs: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Pruned %d finished jobs", count)
+	}
+}
+
+// pruneChallenges deletes expired login challenges, including those of logins
+// that were never completed.
+func (w *workerService) pruneChallenges() {
+	count, err := w.mfaRepo.DeleteExpiredChallenges(time.Now())
+	if err != nil {
+		log.Printf("Error pruning expired login challenges: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Pruned %d expired login challenges", count)
+	}
+}
+
+// scanPendingTasks enqueues tasks that are due for auto-completion under
+// their policy but have no job yet, e.g. tasks whose enqueue failed or that
+// predate the jobs table. Blocked tasks are only enqueued once they can be
+// completed, so that they do not get a new job on every scan.
 func (w *workerService) scanPendingTasks() {
-	duration := time.Duration(w.config.Worker.AutoCompleteMinutes) * time.Minute
-	tasks, err := w.taskRepo.FindPendingTasksOlderThan(duration)
+	tasks, err := w.taskRepo.FindAutoCompleteDue(w.defaultAutoCompleteDelay(), w.config.Subtasks.OnComplete == domain.SubtaskBlock)
 	if err != nil {
 		log.Printf("Error scanning pending tasks: %v", err)
 		return
 	}
 
 	for _, task := range tasks {
-		// Check if task is already being processed
-		if _, exists := w.processedIDs.Load(task.ID); !exists {
-			if err := w.taskRepo.UpdateStatus(task.ID, domain.StatusCompleted); err != nil {
-				log.Printf("Error auto-completing task %s: %v", task.ID, err)
-			} else {
-				log.Printf("Task %s auto-completed by scanner", task.ID)
-			}
+		if _, err := w.enqueue(domain.JobAutoCompleteTask, task.ID, time.Now()); err != nil {
+			log.Printf("Error enqueueing task %s: %v", task.ID, err)
+		}
+	}
+}
+
+// scanDueDates enqueues reminder and overdue jobs that were missed, e.g.
+// because scheduling failed when the task was saved.
+func (w *workerService) scanDueDates() {
+	reminders, err := w.taskRepo.FindRemindersDue()
+	if err != nil {
+		log.Printf("Error scanning reminders: %v", err)
+	}
+	for _, task := range reminders {
+		if _, err := w.enqueue(domain.JobTaskReminder, task.ID, time.Now()); err != nil {
+			log.Printf("Error enqueueing reminder for task %s: %v", task.ID, err)
+		}
+	}
+
+	overdue, err := w.taskRepo.FindOverdueUnmarked()
+	if err != nil {
+		log.Printf("Error scanning overdue tasks: %v", err)
+	}
+	for _, task := range overdue {
+		if _, err := w.enqueue(domain.JobTaskOverdue, task.ID, time.Now()); err != nil {
+			log.Printf("Error enqueueing overdue check for task %s: %v", task.ID, err)
 		}
 	}
 }
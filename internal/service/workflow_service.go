@@ -0,0 +1,166 @@
+package service
+
+import (
+	"time"
+
+	"task-management-api/internal/domain"
+	"task-management-api/internal/policy"
+	"task-management-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type WorkflowService interface {
+	Create(req domain.CreateWorkflowRequest, userID string) (*domain.Workflow, error)
+	List(subject policy.Subject) ([]domain.Workflow, error)
+	GetByID(id string, subject policy.Subject) (*domain.Workflow, error)
+	Delete(id string, subject policy.Subject) error
+}
+
+type workflowService struct {
+	workflowRepo repository.WorkflowRepository
+	orgRepo      repository.OrgRepository
+	policy       *policy.Policy
+}
+
+func NewWorkflowService(workflowRepo repository.WorkflowRepository, orgRepo repository.OrgRepository, p *policy.Policy) WorkflowService {
+	return &workflowService{workflowRepo: workflowRepo, orgRepo: orgRepo, policy: p}
+}
+
+func (s *workflowService) Create(req domain.CreateWorkflowRequest, userID string) (*domain.Workflow, error) {
+	workflow := &domain.Workflow{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		Name:          req.Name,
+		InitialStatus: req.InitialStatus,
+		Statuses:      req.Statuses,
+		Transitions:   req.Transitions,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if workflow.Transitions == nil {
+		workflow.Transitions = []domain.WorkflowTransition{}
+	}
+	if err := workflow.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.workflowRepo.FindByName(userID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrWorkflowExists
+	}
+
+	if err := s.workflowRepo.Create(workflow); err != nil {
+		return nil, err
+	}
+
+	return workflow, nil
+}
+
+// List returns the built-in default workflow followed by those shared with
+// the subject's organization, or by the subject's own outside of one.
+func (s *workflowService) List(subject policy.Subject) ([]domain.Workflow, error) {
+	var workflows []domain.Workflow
+	var err error
+	if subject.OrgID != "" {
+		workflows, err = s.workflowRepo.FindByOrg(subject.OrgID)
+	} else {
+		workflows, err = s.workflowRepo.FindByUser(subject.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append([]domain.Workflow{*domain.DefaultWorkflow}, workflows...), nil
+}
+
+func (s *workflowService) GetByID(id string, subject policy.Subject) (*domain.Workflow, error) {
+	if id == domain.DefaultWorkflowID {
+		return domain.DefaultWorkflow, nil
+	}
+	return s.findOwned(id, subject, policy.WorkflowRead)
+}
+
+func (s *workflowService) Delete(id string, subject policy.Subject) error {
+	if id == domain.DefaultWorkflowID {
+		return domain.ErrWorkflowBuiltIn
+	}
+	if _, err := s.findOwned(id, subject, policy.WorkflowDelete); err != nil {
+		return err
+	}
+
+	inUse, err := s.workflowRepo.IsInUse(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return domain.ErrWorkflowInUse
+	}
+
+	return s.workflowRepo.Delete(id)
+}
+
+// findOwned returns the workflow if the subject may perform the action on it.
+// Workflows the subject may not see are reported as not found.
+func (s *workflowService) findOwned(id string, subject policy.Subject, action policy.Action) (*domain.Workflow, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrWorkflowNotFound
+	}
+	workflow, err := s.workflowRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if workflow == nil {
+		return nil, domain.ErrWorkflowNotFound
+	}
+
+	res, err := workflowResource(s.orgRepo, workflow, subject.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.policy.Can(subject, action, res) {
+		return nil, domain.ErrWorkflowNotFound
+	}
+	return workflow, nil
+}
+
+// workflowResource describes a workflow to the policy. Workflows are shared
+// with every organization their owner belongs to, so the resource is in
+// orgID if the owner is a member of it.
+func workflowResource(orgRepo repository.OrgRepository, workflow *domain.Workflow, orgID string) (policy.Resource, error) {
+	res := policy.OwnedResource(workflow.UserID)
+	if orgID == "" {
+		return res, nil
+	}
+	membership, err := orgRepo.FindMembership(orgID, workflow.UserID)
+	if err != nil {
+		return res, err
+	}
+	if membership != nil {
+		res.OrgID = orgID
+	}
+	return res, nil
+}
+
+// workflowResolver looks up the workflow a task follows.
+type workflowResolver struct {
+	workflowRepo repository.WorkflowRepository
+}
+
+// forTask returns the task's workflow, or the default workflow for tasks
+// without one.
+func (r *workflowResolver) forTask(task *domain.Task) (*domain.Workflow, error) {
+	if task.WorkflowID == nil {
+		return domain.DefaultWorkflow, nil
+	}
+	workflow, err := r.workflowRepo.FindByID(*task.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	if workflow == nil {
+		return nil, domain.ErrWorkflowNotFound
+	}
+	return workflow, nil
+}
@@ -0,0 +1,90 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors,
+// "12345678901234567890" in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d) error = %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %q, want %q", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCodeLowercaseSecret(t *testing.T) {
+	got, err := Code("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", Step(time.Unix(59, 0)))
+	if err != nil || got != "287082" {
+		t.Errorf("Code() = %q, %v, want %q", got, err, "287082")
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code() error = nil, want error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	code := func(step int64) string {
+		c, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		skew     int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", secret: rfcSecret, code: code(current), skew: 1, wantStep: current, wantOK: true},
+		{name: "previous step within skew", secret: rfcSecret, code: code(current - 1), skew: 1, wantStep: current - 1, wantOK: true},
+		{name: "next step within skew", secret: rfcSecret, code: code(current + 1), skew: 1, wantStep: current + 1, wantOK: true},
+		{name: "outside skew", secret: rfcSecret, code: code(current - 2), skew: 1},
+		{name: "no skew rejects previous step", secret: rfcSecret, code: code(current - 1), skew: 0},
+		{name: "wrong code", secret: rfcSecret, code: "000000", skew: 1},
+		{name: "too short", secret: rfcSecret, code: "50471", skew: 1},
+		{name: "too long", secret: rfcSecret, code: "0504710", skew: 1},
+		{name: "empty", secret: rfcSecret, code: "", skew: 1},
+		{name: "invalid secret", secret: "not base32!", code: "050471", skew: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, now, tt.skew)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate() = %d, %v, want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
@@ -1,10 +1,27 @@
 package util
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"errors"
+	"log"
 
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
+	"task-management-api/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Code is a stable,
+// machine-readable error identifier and Errors lists per-field problems.
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     string              `json:"code"`
+	Errors   []domain.FieldError `json:"errors,omitempty"`
 }
 
 type SuccessResponse struct {
@@ -12,15 +29,94 @@ type SuccessResponse struct {
 	Message string      `json:"message,omitempty"`
 }
 
-func SendError(c *fiber.Ctx, status int, message string) error {
-	return c.Status(status).JSON(ErrorResponse{
-		Error:   fiber.ErrBadRequest.Message,
-		Message: message,
-	})
+// SendError writes err as a problem response. Domain errors map to the
+// status of their kind; anything else is logged and reported as a generic
+// internal error so that database details do not leak to clients.
+func SendError(c *fiber.Ctx, err error) error {
+	problem := NewProblem(err)
+	problem.Instance = c.Path()
+	return c.Status(problem.Status).JSON(problem, problemContentType)
+}
+
+func NewProblem(err error) Problem {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		status := statusForKind(domainErr.Kind)
+		return Problem{
+			Type:   "about:blank",
+			Title:  utils.StatusMessage(status),
+			Status: status,
+			Detail: domainErr.Message,
+			Code:   domainErr.Code,
+			Errors: domainErr.Fields,
+		}
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return Problem{
+			Type:   "about:blank",
+			Title:  utils.StatusMessage(fiberErr.Code),
+			Status: fiberErr.Code,
+			Detail: fiberErr.Message,
+			Code:   codeForStatus(fiberErr.Code),
+		}
+	}
+
+	log.Printf("Internal error: %v", err)
+	return Problem{
+		Type:   "about:blank",
+		Title:  utils.StatusMessage(fiber.StatusInternalServerError),
+		Status: fiber.StatusInternalServerError,
+		Code:   "internal_error",
+	}
+}
+
+func statusForKind(kind error) int {
+	switch kind {
+	case domain.ErrValidation:
+		return fiber.StatusBadRequest
+	case domain.ErrUnauthorized:
+		return fiber.StatusUnauthorized
+	case domain.ErrForbidden:
+		return fiber.StatusForbidden
+	case domain.ErrNotFound:
+		return fiber.StatusNotFound
+	case domain.ErrConflict:
+		return fiber.StatusConflict
+	case domain.ErrRateLimited:
+		return fiber.StatusTooManyRequests
+	}
+	return fiber.StatusInternalServerError
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case fiber.StatusBadRequest:
+		return "bad_request"
+	case fiber.StatusUnauthorized:
+		return "unauthorized"
+	case fiber.StatusForbidden:
+		return "forbidden"
+	case fiber.StatusNotFound:
+		return "not_found"
+	case fiber.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case fiber.StatusConflict:
+		return "conflict"
+	case fiber.StatusRequestEntityTooLarge:
+		return "request_too_large"
+	case fiber.StatusTooManyRequests:
+		return "too_many_requests"
+	}
+	if status >= fiber.StatusInternalServerError {
+		return "internal_error"
+	}
+	return "error"
 }
 
 func SendSuccess(c *fiber.Ctx, status int, data interface{}) error {
 	return c.Status(status).JSON(SuccessResponse{
 		Data: data,
 	})
-}
\ No newline at end of file
+}
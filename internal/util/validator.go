@@ -1,38 +1,112 @@
 package util
 
 import (
-	"fmt"
+	"net/url"
 	"regexp"
+	"time"
+	_ "time/tzdata" // timezones do not depend on the host's zoneinfo
+	"unicode/utf8"
+
+	"task-management-api/internal/domain"
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 
+var colorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// localeRegex accepts BCP 47 tags made of a language and optional script,
+// region and variant subtags, like en, pt-BR or zh-Hant-TW.
+var localeRegex = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z]{4})?(-([a-zA-Z]{2}|[0-9]{3}))?(-([a-zA-Z0-9]{5,8}|[0-9][a-zA-Z0-9]{3}))*$`)
+
 func ValidateEmail(email string) error {
 	if email == "" {
-		return fmt.Errorf("email is required")
+		return domain.FieldValidationError("email_required", "email", "email is required")
 	}
 	if !emailRegex.MatchString(email) {
-		return fmt.Errorf("invalid email format")
+		return domain.FieldValidationError("invalid_email", "email", "invalid email format")
 	}
 	return nil
 }
 
 func ValidatePassword(password string) error {
 	if password == "" {
-		return fmt.Errorf("password is required")
+		return domain.FieldValidationError("password_required", "password", "password is required")
 	}
 	if len(password) < 6 {
-		return fmt.Errorf("password must be at least 6 characters")
+		return domain.FieldValidationError("password_too_short", "password", "password must be at least 6 characters")
 	}
 	return nil
 }
 
 func ValidateTaskTitle(title string) error {
 	if title == "" {
-		return fmt.Errorf("title is required")
+		return domain.FieldValidationError("title_required", "title", "title is required")
 	}
 	if len(title) > 255 {
-		return fmt.Errorf("title must be less than 255 characters")
+		return domain.FieldValidationError("title_too_long", "title", "title must be less than 255 characters")
+	}
+	return nil
+}
+
+func ValidateLabelName(name string) error {
+	if name == "" {
+		return domain.FieldValidationError("name_required", "name", "name is required")
+	}
+	if len(name) > 50 {
+		return domain.FieldValidationError("name_too_long", "name", "name must be at most 50 characters")
+	}
+	return nil
+}
+
+func ValidateLabelColor(color string) error {
+	if !colorRegex.MatchString(color) {
+		return domain.FieldValidationError("invalid_color", "color", "color must be a hex value like #1f6feb")
+	}
+	return nil
+}
+
+func ValidateDisplayName(name string) error {
+	if utf8.RuneCountInString(name) > 100 {
+		return domain.FieldValidationError("display_name_too_long", "display_name", "display_name must be at most 100 characters")
+	}
+	return nil
+}
+
+func ValidateTimezone(tz string) error {
+	// LoadLocation also accepts "" and "Local", which depend on the server
+	if tz == "" || tz == "Local" {
+		return domain.FieldValidationError("invalid_timezone", "timezone", "timezone must be an IANA name like Europe/Berlin")
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return domain.FieldValidationError("invalid_timezone", "timezone", "timezone must be an IANA name like Europe/Berlin")
+	}
+	return nil
+}
+
+func ValidateLocale(locale string) error {
+	if !localeRegex.MatchString(locale) {
+		return domain.FieldValidationError("invalid_locale", "locale", "locale must be a language tag like en or pt-BR")
+	}
+	return nil
+}
+
+func ValidateAvatarURL(avatarURL string) error {
+	if len(avatarURL) > 2048 {
+		return domain.FieldValidationError("avatar_url_too_long", "avatar_url", "avatar_url must be at most 2048 characters")
+	}
+	u, err := url.Parse(avatarURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.FieldValidationError("invalid_avatar_url", "avatar_url", "avatar_url must be an http or https URL")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func ValidateAccessTokenName(name string) error {
+	if name == "" {
+		return domain.FieldValidationError("name_required", "name", "name is required")
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return domain.FieldValidationError("name_too_long", "name", "name must be at most 100 characters")
+	}
+	return nil
+}
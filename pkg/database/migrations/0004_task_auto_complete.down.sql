@@ -0,0 +1,3 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS auto_complete_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS auto_complete_after_minutes;
+ALTER TABLE tasks DROP COLUMN IF EXISTS auto_complete_mode;
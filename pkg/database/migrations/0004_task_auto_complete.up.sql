@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS auto_complete_mode VARCHAR(20);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS auto_complete_after_minutes INT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS auto_complete_at TIMESTAMP;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tasks_remind_at;
+DROP INDEX IF EXISTS idx_tasks_due_at;
+
+ALTER TABLE tasks DROP COLUMN overdue_at;
+ALTER TABLE tasks DROP COLUMN reminded_at;
+ALTER TABLE tasks DROP COLUMN remind_at;
+ALTER TABLE tasks DROP COLUMN due_at;
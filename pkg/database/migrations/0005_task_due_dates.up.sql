@@ -0,0 +1,7 @@
+ALTER TABLE tasks ADD COLUMN due_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN remind_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN reminded_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN overdue_at TIMESTAMP;
+
+CREATE INDEX idx_tasks_due_at ON tasks(due_at);
+CREATE INDEX idx_tasks_remind_at ON tasks(remind_at) WHERE reminded_at IS NULL;
@@ -0,0 +1,13 @@
+ALTER TABLE tasks ADD COLUMN priority VARCHAR(20) NOT NULL DEFAULT 'medium';
+
+-- Numeric rank so that priorities sort by importance rather than by name
+ALTER TABLE tasks ADD COLUMN priority_rank SMALLINT GENERATED ALWAYS AS (
+    CASE priority
+        WHEN 'urgent' THEN 4
+        WHEN 'high' THEN 3
+        WHEN 'medium' THEN 2
+        ELSE 1
+    END
+) STORED;
+
+CREATE INDEX idx_tasks_priority_rank ON tasks(priority_rank);
@@ -0,0 +1,5 @@
+-- Deleting a parent is handled by the application according to
+-- SUBTASK_ON_DELETE; the foreign key only guarantees no dangling references.
+ALTER TABLE tasks ADD COLUMN parent_id UUID REFERENCES tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_parent_id ON tasks(parent_id);
@@ -0,0 +1,10 @@
+-- task_id is blocked by blocked_by_id until that task is completed
+CREATE TABLE task_dependencies (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_by_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, blocked_by_id),
+    CHECK (task_id <> blocked_by_id)
+);
+
+CREATE INDEX idx_task_dependencies_blocked_by_id ON task_dependencies(blocked_by_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tasks_workflow_id;
+DROP INDEX IF EXISTS idx_tasks_status_category;
+ALTER TABLE tasks DROP COLUMN IF EXISTS status_category;
+ALTER TABLE tasks DROP COLUMN IF EXISTS workflow_id;
+DROP TABLE IF EXISTS workflows;
@@ -0,0 +1,27 @@
+CREATE TABLE workflows (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    initial_status VARCHAR(50) NOT NULL,
+    statuses JSONB NOT NULL,
+    transitions JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Tasks without a workflow use the built-in pending/in_progress/completed one
+ALTER TABLE tasks ADD COLUMN workflow_id UUID REFERENCES workflows(id);
+
+-- The category of the current status, so queries can tell open from closed
+-- tasks without knowing each workflow
+ALTER TABLE tasks ADD COLUMN status_category VARCHAR(20) NOT NULL DEFAULT 'open';
+
+UPDATE tasks SET status_category = CASE status
+    WHEN 'completed' THEN 'closed'
+    WHEN 'in_progress' THEN 'active'
+    ELSE 'open'
+END;
+
+CREATE INDEX idx_tasks_status_category ON tasks(status_category);
+CREATE INDEX idx_tasks_workflow_id ON tasks(workflow_id);
@@ -0,0 +1,26 @@
+-- tasks.user_id remains the creator; assignees are tracked separately
+CREATE TABLE task_assignees (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    assigned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_assignees_user_id ON task_assignees(user_id);
+
+-- Audit trail of assignment changes
+CREATE TABLE task_assignment_history (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    changed_by UUID,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_assignment_history_task_id ON task_assignment_history(task_id, changed_at);
+
+-- Existing tasks were implicitly assigned to their creator
+INSERT INTO task_assignees (task_id, user_id, assigned_by, assigned_at)
+SELECT id, user_id, user_id, created_at FROM tasks;
@@ -0,0 +1,5 @@
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS org_id;
+DROP INDEX IF EXISTS idx_tasks_org_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS org_id;
+DROP TABLE IF EXISTS org_memberships;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,34 @@
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE org_memberships (
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (org_id, user_id)
+);
+
+CREATE INDEX idx_org_memberships_user_id ON org_memberships(user_id);
+
+-- Every existing user gets a personal organization owning their tasks. It
+-- reuses the user's ID, which needs no mapping table for the backfill.
+INSERT INTO organizations (id, name, created_by, created_at, updated_at)
+SELECT id, 'Personal', id, created_at, created_at FROM users;
+
+INSERT INTO org_memberships (org_id, user_id, role, created_at)
+SELECT id, id, 'owner', created_at FROM users;
+
+ALTER TABLE tasks ADD COLUMN org_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE tasks SET org_id = user_id;
+ALTER TABLE tasks ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX idx_tasks_org_id ON tasks(org_id, created_at);
+
+-- Refreshed tokens stay in the organization the session was switched to
+ALTER TABLE refresh_tokens ADD COLUMN org_id UUID REFERENCES organizations(id) ON DELETE SET NULL;
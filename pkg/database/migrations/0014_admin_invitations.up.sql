@@ -0,0 +1,13 @@
+-- Invitations to become a platform admin. Only a hash of the token is
+-- stored; the token itself is handed out once, when the invitation is made.
+CREATE TABLE admin_invitations (
+    id UUID PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_admin_invitations_email ON admin_invitations(email);
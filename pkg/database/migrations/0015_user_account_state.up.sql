@@ -0,0 +1,6 @@
+-- Disabled users cannot log in; users flagged by an admin must choose a new
+-- password at their next login
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_users_created_at ON users(created_at);
@@ -0,0 +1,18 @@
+ALTER TABLE users ADD COLUMN display_name VARCHAR(100);
+ALTER TABLE users ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE users ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT 'en';
+ALTER TABLE users ADD COLUMN avatar_url VARCHAR(2048);
+
+-- Set when a user deletes their account and it is anonymized rather than
+-- removed
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+-- A requested change of email address, applied once the new address is
+-- confirmed. A user has at most one pending change.
+CREATE TABLE email_changes (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    new_email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
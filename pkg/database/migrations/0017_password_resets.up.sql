@@ -0,0 +1,12 @@
+-- Tokens for resetting a forgotten password. Like invitations, only a hash
+-- of the token is stored; used_at makes each token single-use.
+CREATE TABLE password_resets (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_password_resets_user_created ON password_resets(user_id, created_at);
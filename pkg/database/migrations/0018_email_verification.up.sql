@@ -0,0 +1,15 @@
+-- Users confirm their email address with a mailed token. Accounts that
+-- existed before count as verified.
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+UPDATE users SET email_verified_at = created_at;
+
+-- The pending verification of a user. The token only verifies the address
+-- it was sent to.
+CREATE TABLE email_verifications (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
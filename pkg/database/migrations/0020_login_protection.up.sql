@@ -0,0 +1,25 @@
+-- Failed login counters, one row per account email and per client IP.
+-- failures counts the failures since the window started at window_start.
+CREATE TABLE login_attempts (
+    kind VARCHAR(10) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    window_start TIMESTAMP NOT NULL,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+    PRIMARY KEY (kind, key)
+);
+
+-- Audit trail of security-relevant events such as lockouts.
+CREATE TABLE security_events (
+    id UUID PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    email VARCHAR(255),
+    ip VARCHAR(64),
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_security_events_created_at ON security_events(created_at);
+CREATE INDEX idx_security_events_user_id ON security_events(user_id);
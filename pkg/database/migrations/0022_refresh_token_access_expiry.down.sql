@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_expires_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS access_expires_at;
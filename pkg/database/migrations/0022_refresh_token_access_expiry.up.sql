@@ -0,0 +1,8 @@
+-- Revoking a session records the access tokens issued with it until they
+-- expire, which is much sooner than the refresh token. Older sessions did not
+-- store that time and fall back to the refresh token's.
+ALTER TABLE refresh_tokens ADD COLUMN access_expires_at TIMESTAMP;
+UPDATE refresh_tokens SET access_expires_at = expires_at;
+ALTER TABLE refresh_tokens ALTER COLUMN access_expires_at SET NOT NULL;
+
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
@@ -0,0 +1 @@
+ALTER TABLE mfa_challenges DROP COLUMN IF EXISTS new_password_hash;
@@ -0,0 +1,3 @@
+-- A login that must replace the password keeps the new password hash on its
+-- challenge, so it is only stored once the second factor has been checked.
+ALTER TABLE mfa_challenges ADD COLUMN new_password_hash VARCHAR(255);
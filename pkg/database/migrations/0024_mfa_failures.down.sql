@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_mfa_challenges_expires_at;
+ALTER TABLE user_mfa DROP COLUMN IF EXISTS last_failure_at;
+ALTER TABLE user_mfa DROP COLUMN IF EXISTS failed_attempts;
@@ -0,0 +1,6 @@
+-- Wrong codes are counted per user across login challenges, since every
+-- password login starts a new challenge with a fresh attempt count.
+ALTER TABLE user_mfa ADD COLUMN failed_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_mfa ADD COLUMN last_failure_at TIMESTAMP;
+
+CREATE INDEX idx_mfa_challenges_expires_at ON mfa_challenges(expires_at);
@@ -0,0 +1,2 @@
+-- Finished jobs are deleted once they are older than the retention period.
+CREATE INDEX idx_jobs_finished ON jobs(updated_at) WHERE status IN ('done', 'dead');
@@ -27,36 +27,17 @@ func NewPostgresDB(dsn string) (*DB, error) {
 	return &DB{db}, nil
 }
 
+// Migrate applies all pending schema migrations.
 func (db *DB) Migrate() error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS users (
-			id UUID PRIMARY KEY,
-			email VARCHAR(255) UNIQUE NOT NULL,
-			password VARCHAR(255) NOT NULL,
-			role VARCHAR(50) NOT NULL DEFAULT 'user',
-			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-		)`,
-		`CREATE TABLE IF NOT EXISTS tasks (
-			id UUID PRIMARY KEY,
-			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-			title VARCHAR(255) NOT NULL,
-			description TEXT,
-			status VARCHAR(50) NOT NULL DEFAULT 'pending',
-			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks(user_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status)`,
-		`CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at)`,
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("migration failed: %w", err)
 	}
 
-	for _, query := range queries {
-		if _, err := db.Exec(query); err != nil {
-			return fmt.Errorf("migration failed: %w", err)
-		}
+	if err := migrator.Up(); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
 	}
 
 	log.Println("Database migration completed")
 	return nil
-}
\ No newline at end of file
+}